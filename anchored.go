@@ -0,0 +1,115 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAnchorInterval is how often an AnchoredClock re-reads its
+	// source unless told otherwise
+	DefaultAnchorInterval = time.Minute
+	// DefaultMaxSlew is the fastest an AnchoredClock slews towards its
+	// source unless told otherwise, 500 ppm like the NTP kernel discipline
+	DefaultMaxSlew = 500e-6
+)
+
+// AnchoredClock is a Clock that reads its source once and then advances
+// with Go's monotonic clock, so intervals between its timestamps survive
+// steps of the wall clock. Every interval it reads the source again and
+// slews towards it, never faster than its maximum slew rate, so it never
+// steps and never runs backwards. It is safe for concurrent use.
+type AnchoredClock struct {
+	source   Clock
+	interval time.Duration
+	maxSlew  float64
+	monoNow  func() time.Time
+
+	mu      sync.Mutex
+	anchor  time.Time
+	base    TAIN
+	pending time.Duration
+}
+
+// NewAnchoredClock returns an AnchoredClock reading source, or SystemClock
+// if source is nil, every interval and slewing at most maxSlew seconds per
+// second. Zero arguments select DefaultAnchorInterval and DefaultMaxSlew,
+// it panics if interval is negative or maxSlew is outside [0, 1).
+func NewAnchoredClock(source Clock, interval time.Duration, maxSlew float64) *AnchoredClock {
+	if interval < 0 {
+		panic("glibtai: negative interval for NewAnchoredClock")
+	}
+	if maxSlew < 0 || maxSlew >= 1 || math.IsNaN(maxSlew) {
+		panic("glibtai: slew rate out of range for NewAnchoredClock")
+	}
+	if source == nil {
+		source = SystemClock{}
+	}
+
+	c := &AnchoredClock{
+		source:   source,
+		interval: interval,
+		maxSlew:  maxSlew,
+		monoNow:  time.Now,
+	}
+	if c.interval == 0 {
+		c.interval = DefaultAnchorInterval
+	}
+	if c.maxSlew == 0 {
+		c.maxSlew = DefaultMaxSlew
+	}
+	c.anchor = c.monoNow()
+	c.base = source.TAINNow()
+	return c
+}
+
+// TAINow returns the current timestamp in TAI struct
+func (c *AnchoredClock) TAINow() TAI {
+	return TAIfromTAIN(c.TAINNow())
+}
+
+// TAINNow returns the current timestamp in TAIN struct
+func (c *AnchoredClock) TAINNow() TAIN {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.monoNow()
+	elapsed := now.Sub(c.anchor)
+	t := TAINAdd(c.base, elapsed+c.slewed(elapsed))
+	if elapsed >= c.interval {
+		c.reanchor(now, t)
+	}
+	return t
+}
+
+// Offset returns how far the clock is from its source at the last anchor,
+// positive when the source was ahead, less what has been slewed since
+func (c *AnchoredClock) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending - c.slewed(c.monoNow().Sub(c.anchor))
+}
+
+// slewed returns the part of the pending correction applied elapsed after
+// the anchor
+func (c *AnchoredClock) slewed(elapsed time.Duration) time.Duration {
+	limit := time.Duration(float64(elapsed) * c.maxSlew)
+	return min(max(c.pending, -limit), limit)
+}
+
+// reanchor makes t, read at now, the new anchor and computes the
+// correction towards the source
+func (c *AnchoredClock) reanchor(now time.Time, t TAIN) {
+	target := c.source.TAINNow()
+	c.anchor = now
+	c.base = t
+	c.pending = time.Duration(int64(tainNanos(target) - tainNanos(t)))
+}
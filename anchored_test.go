@@ -0,0 +1,113 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+// steppedSource is a Clock whose reading can be changed at will
+type steppedSource struct {
+	t TAIN
+}
+
+func (s *steppedSource) TAINow() TAI {
+	return TAIfromTAIN(s.t)
+}
+
+func (s *steppedSource) TAINNow() TAIN {
+	return s.t
+}
+
+func TestAnchoredClockIgnoresSteps(t *testing.T) {
+	start := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	source := &steppedSource{t: start}
+	mono := time.Now()
+	c := NewAnchoredClock(source, time.Minute, 500e-6)
+	c.monoNow = func() time.Time { return mono }
+	c.anchor = mono
+
+	mono = mono.Add(10 * time.Second)
+	source.t = TAINAdd(start, -time.Hour) // the wall clock is stepped back
+	if now, expected := c.TAINNow(), TAINAdd(start, 10*time.Second); now != expected {
+		t.Errorf("TAINNow() = %v, expected %v", now, expected)
+	}
+}
+
+func TestAnchoredClockSlews(t *testing.T) {
+	start := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	source := &steppedSource{t: start}
+	mono := time.Now()
+	c := NewAnchoredClock(source, time.Minute, 0.001)
+	c.monoNow = func() time.Time { return mono }
+	c.anchor = mono
+
+	// the source is stepped back by a second, the clock re-anchors
+	mono = mono.Add(time.Minute)
+	source.t = TAINAdd(start, time.Minute-time.Second)
+	prev := c.TAINNow()
+	if offset := c.Offset(); offset != -time.Second {
+		t.Fatalf("Offset() = %v after re-anchoring, expected -1s", offset)
+	}
+
+	// it slews at 1 ms per second and never runs backwards
+	for i := 1; i <= 100; i++ {
+		mono = mono.Add(time.Second)
+		source.t = TAINAdd(source.t, time.Second)
+		now := c.TAINNow()
+		d, _ := TAINSub(now, prev)
+		if d != time.Second-time.Millisecond {
+			t.Fatalf("step %d advanced by %v, expected 999ms", i, d)
+		}
+		prev = now
+	}
+
+	// 60 ms were slewed before the re-anchor at 60 s and 40 ms after it
+	if offset := c.Offset(); offset != -time.Second+100*time.Millisecond {
+		t.Errorf("Offset() = %v, expected -900ms", offset)
+	}
+}
+
+func TestAnchoredClockDefaults(t *testing.T) {
+	c := NewAnchoredClock(nil, 0, 0)
+	if c.interval != DefaultAnchorInterval || c.maxSlew != DefaultMaxSlew {
+		t.Errorf("defaults not applied: %v %v", c.interval, c.maxSlew)
+	}
+	if c.source != (SystemClock{}) {
+		t.Errorf("nil source read as %v, expected SystemClock", c.source)
+	}
+	a := c.TAINNow()
+	b := c.TAINow()
+	if b.x < a.sec {
+		t.Errorf("TAINow() = %v before TAINNow() = %v", b, a)
+	}
+}
+
+func TestAnchoredClockInvalidArguments(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		maxSlew  float64
+	}{
+		{-time.Second, DefaultMaxSlew},
+		{time.Minute, -0.001},
+		{time.Minute, 1},
+		{time.Minute, 2},
+	}
+
+	for _, tc := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("NewAnchoredClock(%v, %v) did not panic", tc.interval, tc.maxSlew)
+				}
+			}()
+			NewAnchoredClock(SystemClock{}, tc.interval, tc.maxSlew)
+		}()
+	}
+}
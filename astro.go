@@ -0,0 +1,183 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// AstroScale selects an astronomical time scale derived from TAI
+type AstroScale int
+
+const (
+	// TT is Terrestrial Time, TAI + 32.184s
+	TT AstroScale = iota
+	// TCG is Geocentric Coordinate Time, which runs faster than TT by
+	// L_G = 6.969290134e-10 and agreed with it at 1977-01-01T00:00:32.184 TT
+	TCG
+	// TCB is Barycentric Coordinate Time, which runs faster than TDB by
+	// L_B = 1.550519768e-8 and agreed with it at 1977-01-01T00:00:32.184 TT
+	// up to TDB0 = -6.55e-5s
+	TCB
+	// TDB is Barycentric Dynamical Time, TT plus a periodic term of at
+	// most 1.7ms. glibtai uses the two term series of the Astronomical
+	// Almanac, good to some microseconds.
+	TDB
+)
+
+const (
+	// jdUnix is the Julian Date of 1970-01-01T00:00:00
+	jdUnix = 2440587.5
+	// jdMJD is the Julian Date of MJD 0
+	jdMJD = 2400000.5
+	// jdJ2000 is the Julian Date of J2000.0
+	jdJ2000 = 2451545.0
+	// jd1977 is the Julian Date of 1977-01-01T00:00:00
+	jd1977 = 2443144.5
+	// ttMinusTAI is TT - TAI in days
+	ttMinusTAI = 32.184 / 86400
+	// tdb0 is TDB - TCB at 1977-01-01T00:00:32.184 TT in days
+	tdb0 = -6.55e-5 / 86400
+	// elg and elb are L_G and L_B
+	elg = 6.969290134e-10
+	elb = 1.550519768e-8
+)
+
+func (s AstroScale) String() string {
+	switch s {
+	case TT:
+		return "TT"
+	case TCG:
+		return "TCG"
+	case TCB:
+		return "TCB"
+	case TDB:
+		return "TDB"
+	default:
+		return "AstroScale(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// JulianDate is a Julian Date split like SOFA does to keep nanosecond
+// precision: Day holds whole days ending at midnight, Frac the fraction
+// of the day since then. It is the floating point form astronomical
+// software expects in TT, TCG, TCB and TDB. The JD and MJD types are
+// exact day numbers and nanoseconds in UTC or TAI, use them for civil
+// dates and for days with leap seconds.
+type JulianDate struct {
+	Day  float64
+	Frac float64
+}
+
+// JulianDatefromMJD returns the Julian Date of a Modified Julian Date
+// split into days and a fraction of the day
+func JulianDatefromMJD(day, frac float64) JulianDate {
+	return JulianDate{Day: day + jdMJD, Frac: frac}
+}
+
+// Float returns j as a single number, with about 20µs precision today
+func (j JulianDate) Float() float64 {
+	return j.Day + j.Frac
+}
+
+// MJD returns j as a Modified Julian Date split into days and a fraction
+// of the day, in the scale of j. For the exact MJD type of a timestamp
+// use TAINMJD.
+func (j JulianDate) MJD() (day, frac float64) {
+	return j.Day - jdMJD, j.Frac
+}
+
+// add returns j plus days, keeping Frac in [0, 1)
+func (j JulianDate) add(days float64) JulianDate {
+	j.Frac += days
+	whole := math.Floor(j.Frac)
+	return JulianDate{Day: j.Day + whole, Frac: j.Frac - whole}
+}
+
+// since1977 returns the days since 1977-01-01T00:00:32.184 in the scale
+// of j
+func (j JulianDate) since1977() float64 {
+	return (j.Day - jd1977) + (j.Frac - ttMinusTAI)
+}
+
+// JulianDatefromTAIN returns t as a Julian Date in scale s
+func JulianDatefromTAIN(t TAIN, s AstroScale) JulianDate {
+	tt := taiJulianDate(t).add(ttMinusTAI)
+	switch s {
+	case TCG:
+		return tt.add(tt.since1977() * elg / (1 - elg))
+	case TDB:
+		return tt.add(tdbMinusTT(tt))
+	case TCB:
+		tdb := tt.add(tdbMinusTT(tt))
+		return tdb.add(-tdb0 + (tdb.since1977()-tdb0)*elb/(1-elb))
+	default:
+		return tt
+	}
+}
+
+// TAINfromJulianDate returns the TAIN timestamp of a Julian Date in scale
+// s, rounded to the nanosecond
+func TAINfromJulianDate(j JulianDate, s AstroScale) TAIN {
+	var tt JulianDate
+	switch s {
+	case TCG:
+		tt = j.add(-j.since1977() * elg)
+	case TDB:
+		tt = j.add(-tdbMinusTT(j))
+	case TCB:
+		tdb := j.add(tdb0 - j.since1977()*elb)
+		tt = tdb.add(-tdbMinusTT(tdb))
+	default:
+		tt = j.add(0)
+	}
+
+	tai := tt.add(-ttMinusTAI)
+	days, frac := math.Modf(tai.Day - jdUnix)
+	secs := (frac + tai.Frac) * 86400
+	whole := math.Floor(secs)
+	nano := math.Round((secs - whole) * 1e9)
+	if nano >= 1e9 {
+		whole++
+		nano = 0
+	}
+	sec := int64(days)*86400 + int64(whole)
+	return TAIN{sec: tai64Epoch + uint64(sec), nano: uint32(nano)}
+}
+
+// TAINAstroOffset returns the reading of scale s minus TAI at t
+func TAINAstroOffset(t TAIN, s AstroScale) time.Duration {
+	tai := taiJulianDate(t)
+	j := JulianDatefromTAIN(t, s)
+	days := (j.Day - tai.Day) + (j.Frac - tai.Frac)
+	return time.Duration(math.Round(days * 86400 * 1e9))
+}
+
+// taiJulianDate returns t as a Julian Date in TAI, the exact MJD of t in
+// floating point
+func taiJulianDate(t TAIN) JulianDate {
+	m := TAINMJD(t, ScaleTAI)
+	return JulianDatefromMJD(float64(m.Day), m.Frac(ScaleTAI))
+}
+
+// tdbMinusTT returns TDB - TT in days at j, in TT or TDB
+func tdbMinusTT(j JulianDate) float64 {
+	g := (357.53 + 0.98560028*((j.Day-jdJ2000)+j.Frac)) * math.Pi / 180
+	return (0.001657*math.Sin(g) + 0.000014*math.Sin(2*g)) / 86400
+}
+
+// floorDiv returns a / b rounded towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,96 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAstroTT(t *testing.T) {
+	// J2000.0 is 2000-01-01T12:00:00 TT, 11:59:27.816 TAI and 11:58:55.816 UTC
+	tain := TAINfromTime(time.Date(2000, time.January, 1, 11, 58, 55, 816000000, time.UTC))
+	j := JulianDatefromTAIN(tain, TT)
+	if j.Day != 2451544.5 || math.Abs(j.Frac-0.5) > 1e-14 {
+		t.Errorf("J2000.0 is %+v", j)
+	}
+	if d := TAINAstroOffset(tain, TT); d != 32184*time.Millisecond {
+		t.Errorf("TT - TAI is %v", d)
+	}
+	if x := TAINfromJulianDate(JulianDate{Day: 2451545.0}, TT); x != tain {
+		t.Errorf("JD 2451545.0 TT is %v, expected %v", x, tain)
+	}
+}
+
+func TestAstroTCG(t *testing.T) {
+	// reference values from the SOFA iauTttcg and iauTcgtt tests
+	tain := TAINfromJulianDate(JulianDate{Day: 2453750.5, Frac: 0.892482639}, TT)
+	if j := JulianDatefromTAIN(tain, TCG); j.Day != 2453750.5 || math.Abs(j.Frac-0.8924900312508587113) > 1e-12 {
+		t.Errorf("TCG is %+v", j)
+	}
+	tain = TAINfromJulianDate(JulianDate{Day: 2453750.5, Frac: 0.892862531}, TCG)
+	if j := JulianDatefromTAIN(tain, TT); j.Day != 2453750.5 || math.Abs(j.Frac-0.8928551387488816828) > 1e-12 {
+		t.Errorf("TT is %+v", j)
+	}
+}
+
+func TestAstroTDBAndTCB(t *testing.T) {
+	j2000 := TAINfromJulianDate(JulianDate{Day: 2451545.0}, TT)
+	d := TAINAstroOffset(j2000, TDB) - TAINAstroOffset(j2000, TT)
+	if d < -80*time.Microsecond || d > -60*time.Microsecond {
+		t.Errorf("TDB - TT at J2000.0 is %v", d)
+	}
+
+	// TCB - TDB grows by L_B from 1977, about 11.25s at J2000.0
+	d = TAINAstroOffset(j2000, TCB) - TAINAstroOffset(j2000, TDB)
+	if d < 11253*time.Millisecond || d > 11255*time.Millisecond {
+		t.Errorf("TCB - TDB at J2000.0 is %v", d)
+	}
+	for y := 1980; y < 2100; y += 7 {
+		tain := TAINfromTime(time.Date(y, time.March, 1, 0, 0, 0, 0, time.UTC))
+		d := TAINAstroOffset(tain, TDB) - TAINAstroOffset(tain, TT)
+		if d < -1700*time.Microsecond || d > 1700*time.Microsecond {
+			t.Errorf("TDB - TT in %d is %v", y, d)
+		}
+	}
+}
+
+func TestAstroRoundTrip(t *testing.T) {
+	for _, s := range []AstroScale{TT, TCG, TCB, TDB} {
+		for _, tm := range []time.Time{
+			time.Date(1972, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, time.February, 14, 19, 31, 10, 123456789, time.UTC),
+			time.Date(2100, time.December, 31, 23, 59, 59, 999999999, time.UTC),
+		} {
+			tain := TAINfromTime(tm)
+			x := TAINfromJulianDate(JulianDatefromTAIN(tain, s), s)
+			if d, _ := TAINSub(x, tain); d < -time.Nanosecond || d > time.Nanosecond {
+				t.Errorf("%v round trip of %v is off by %v", s, tm, d)
+			}
+		}
+	}
+}
+
+func TestJulianDateMJD(t *testing.T) {
+	j := JulianDatefromMJD(58163, 0.25)
+	if j.Day != 2458163.5 || j.Frac != 0.25 || j.Float() != 2458163.75 {
+		t.Errorf("MJD 58163.25 is %+v", j)
+	}
+	if day, frac := j.MJD(); day != 58163 || frac != 0.25 {
+		t.Errorf("MJD() = %v, %v", day, frac)
+	}
+}
+
+func TestAstroScaleString(t *testing.T) {
+	for s, str := range map[AstroScale]string{TT: "TT", TCG: "TCG", TCB: "TCB", TDB: "TDB", 9: "AstroScale(9)"} {
+		if s.String() != str {
+			t.Errorf("%d.String() = %s", int(s), s.String())
+		}
+	}
+}
@@ -67,6 +67,15 @@ func BenchmarkTAIString(b *testing.B) {
 	}
 }
 
+func BenchmarkTAIAppendLabel(b *testing.B) {
+	tai := TAINow()
+	buf := make([]byte, 0, TAILabelLength)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = tai.AppendLabel(buf[:0])
+	}
+}
+
 func BenchmarkTAIfromString(b *testing.B) {
 	tai := TAINow()
 	str := tai.String()
@@ -141,6 +150,23 @@ func BenchmarkTAINString(b *testing.B) {
 	}
 }
 
+func BenchmarkTAINAppendLabel(b *testing.B) {
+	tain := TAINNow()
+	buf := make([]byte, 0, TAINLabelLength)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = tain.AppendLabel(buf[:0])
+	}
+}
+
+func BenchmarkTAINLabel(b *testing.B) {
+	tain := TAINNow()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tain.Label()
+	}
+}
+
 func BenchmarkTAINfromString(b *testing.B) {
 	tain := TAINNow()
 	str := tain.String()
@@ -158,26 +184,35 @@ func BenchmarkTAINfromTime(b *testing.B) {
 	}
 }
 
-func BenchmarkLsoffset(b *testing.B) {
-	now := time.Now()
+func BenchmarkUTCOffset(b *testing.B) {
+	now := time.Now().Unix()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		lsoffset(now)
+		utcOffset(now)
 	}
 }
 
-func BenchmarkLsoffsetHistoric(b *testing.B) {
-	historic := time.Date(1985, time.July, 1, 0, 0, 0, 0, time.UTC)
+func BenchmarkUTCOffsetHistoric(b *testing.B) {
+	historic := time.Date(1985, time.July, 1, 0, 0, 0, 0, time.UTC).Unix()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		lsoffset(historic)
+		utcOffset(historic)
 	}
 }
 
-func BenchmarkLsoffsetPreLeap(b *testing.B) {
-	preLeap := time.Date(1971, time.January, 1, 0, 0, 0, 0, time.UTC)
+func BenchmarkUTCOffsetPreLeap(b *testing.B) {
+	preLeap := time.Date(1971, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		lsoffset(preLeap)
+		utcOffset(preLeap)
 	}
 }
+
+func BenchmarkMonotonicNext(b *testing.B) {
+	m := NewMonotonic(SystemClock{})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Next()
+		}
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"strconv"
+	"time"
+)
+
+// Scale selects the time scale calendar time is expressed in
+type Scale int
+
+const (
+	// ScaleUTC expresses calendar time in UTC, an inserted leap second
+	// is shown as second 60 of the last minute of the day
+	ScaleUTC Scale = iota
+	// ScaleTAI expresses calendar time in TAI, which has no leap seconds
+	ScaleTAI
+)
+
+func (s Scale) String() string {
+	switch s {
+	case ScaleUTC:
+		return "UTC"
+	case ScaleTAI:
+		return "TAI"
+	default:
+		return "Scale(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// caltime is a broken down calendar time, like libtai's struct caltime
+// but without a time zone offset
+type caltime struct {
+	year    int
+	month   time.Month
+	day     int
+	hour    int
+	minute  int
+	second  int
+	nano    int
+	yday    int
+	weekday time.Weekday
+}
+
+// caltimeFromLabel breaks a TAI64 label down into calendar time in scale
+func caltimeFromLabel(sec uint64, nano uint32, scale Scale) caltime {
+	var unix int64
+	leap := false
+	if scale == ScaleTAI {
+		unix = int64(sec - tai64Epoch)
+	} else {
+		unix, leap = unixFromLabel(sec)
+	}
+
+	tm := time.Unix(unix, int64(nano)).UTC()
+	ct := caltime{
+		year:    tm.Year(),
+		month:   tm.Month(),
+		day:     tm.Day(),
+		hour:    tm.Hour(),
+		minute:  tm.Minute(),
+		second:  tm.Second(),
+		nano:    tm.Nanosecond(),
+		yday:    tm.YearDay(),
+		weekday: tm.Weekday(),
+	}
+	if leap {
+		ct.second = 60
+	}
+	return ct
+}
+
+// label returns the TAI64 label of ct in scale, offset is the number of
+// seconds ct is east of the scale. Second 60 is only accepted when it is
+// an inserted leap second and a second removed by a negative leap second
+// is not accepted at all.
+func (ct caltime) label(scale Scale, offset int) (uint64, bool) {
+	second := ct.second
+	if second == 60 {
+		second = 59
+	}
+	unix := time.Date(ct.year, ct.month, ct.day, ct.hour, ct.minute, second, 0, time.UTC).Unix()
+	unix -= int64(offset)
+
+	switch {
+	case scale == ScaleTAI && ct.second < 60:
+		return tai64Epoch + uint64(unix), true
+	case scale == ScaleTAI:
+		return 0, false
+	case ct.second < 60:
+		return labelFromUnix(unix), !unixSkipped(unix)
+	}
+
+	x := labelFromUnix(unix + 1)
+	if u, leap := unixFromLabel(x - 1); !leap || u != unix {
+		return 0, false
+	}
+	return x - 1, true
+}
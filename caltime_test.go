@@ -0,0 +1,119 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaltimeFromLabel(t *testing.T) {
+	leap := labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()) - 1
+	tests := []struct {
+		name   string
+		label  uint64
+		scale  Scale
+		hour   int
+		minute int
+		second int
+	}{
+		{"leap second in UTC", leap, ScaleUTC, 23, 59, 60},
+		{"leap second in TAI", leap, ScaleTAI, 0, 0, 36},
+		{"after leap second in UTC", leap + 1, ScaleUTC, 0, 0, 0},
+		{"before leap second in UTC", leap - 1, ScaleUTC, 23, 59, 59},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct := caltimeFromLabel(tc.label, 0, tc.scale)
+			if ct.hour != tc.hour || ct.minute != tc.minute || ct.second != tc.second {
+				t.Errorf("Expected %02d:%02d:%02d, got %02d:%02d:%02d",
+					tc.hour, tc.minute, tc.second, ct.hour, ct.minute, ct.second)
+			}
+		})
+	}
+}
+
+func TestCaltimeLabel(t *testing.T) {
+	midnight := labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+	tests := []struct {
+		name   string
+		ct     caltime
+		scale  Scale
+		offset int
+		label  uint64
+		ok     bool
+	}{
+		{"leap second", caltime{year: 2016, month: 12, day: 31, hour: 23, minute: 59, second: 60},
+			ScaleUTC, 0, midnight - 1, true},
+		{"leap second east of UTC", caltime{year: 2017, month: 1, day: 1, hour: 1, minute: 59, second: 60},
+			ScaleUTC, 7200, midnight - 1, true},
+		{"not a leap second", caltime{year: 2016, month: 12, day: 30, hour: 23, minute: 59, second: 60},
+			ScaleUTC, 0, 0, false},
+		{"second 60 in TAI", caltime{year: 2016, month: 12, day: 31, hour: 23, minute: 59, second: 60},
+			ScaleTAI, 0, 0, false},
+		{"TAI midnight", caltime{year: 2017, month: 1, day: 1, second: 37},
+			ScaleTAI, 0, midnight, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			label, ok := tc.ct.label(tc.scale, tc.offset)
+			if label != tc.label || ok != tc.ok {
+				t.Errorf("Expected %d, %v, got %d, %v", tc.label, tc.ok, label, ok)
+			}
+		})
+	}
+}
+
+func TestScaleString(t *testing.T) {
+	if s := ScaleUTC.String(); s != "UTC" {
+		t.Errorf("ScaleUTC.String() = %s", s)
+	}
+	if s := ScaleTAI.String(); s != "TAI" {
+		t.Errorf("ScaleTAI.String() = %s", s)
+	}
+	if s := Scale(7).String(); s != "Scale(7)" {
+		t.Errorf("Scale(7).String() = %s", s)
+	}
+}
+
+func TestCaltimeNegativeLeapSecond(t *testing.T) {
+	withNegativeLeap(t)
+	midnight := labelFromUnix(negativeLeapMidnight.Unix())
+
+	ct := caltimeFromLabel(midnight-1, 0, ScaleUTC)
+	if ct.hour != 23 || ct.minute != 59 || ct.second != 58 {
+		t.Errorf("second before midnight is %+v", ct)
+	}
+	removed := caltime{year: 2029, month: 12, day: 31, hour: 23, minute: 59, second: 59}
+	if _, ok := removed.label(ScaleUTC, 0); ok {
+		t.Error("removed second accepted in UTC")
+	}
+	if _, ok := removed.label(ScaleTAI, 0); !ok {
+		t.Error("23:59:59 rejected in TAI")
+	}
+	removed.second = 60
+	if _, ok := removed.label(ScaleUTC, 0); ok {
+		t.Error("second 60 accepted on a day with a removed second")
+	}
+
+	if _, err := TAINParse(time.DateTime, "2029-12-31 23:59:59", ScaleUTC); err == nil {
+		t.Error("TAINParse accepted the removed second")
+	}
+	if _, err := TAINParseRFC3339("2029-12-31T23:59:59Z", RFC3339Strict); err == nil {
+		t.Error("TAINParseRFC3339 accepted the removed second")
+	}
+	x, err := TAINParseRFC3339("2029-12-31T23:59:58.5Z", RFC3339Strict)
+	if err != nil || x != (TAIN{sec: midnight - 1, nano: 5e8}) {
+		t.Errorf("TAINParseRFC3339 returned %v, %v", x, err)
+	}
+	if s := TAINFormatRFC3339(TAIN{sec: midnight - 1, nano: 5e8}); s != "2029-12-31T23:59:58.5Z" {
+		t.Errorf("TAINFormatRFC3339 = %s", s)
+	}
+}
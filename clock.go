@@ -0,0 +1,67 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+// Clock is a source of TAI timestamps. Code that takes a Clock instead of
+// calling TAINow and TAINNow directly can be tested with the fake clock of
+// the taitest package.
+type Clock interface {
+	// TAINow returns the current timestamp in TAI struct
+	TAINow() TAI
+	// TAINNow returns the current timestamp in TAIN struct
+	TAINNow() TAIN
+}
+
+// SystemClock is a Clock reading the system clock and converting it with
+// the leap second table
+type SystemClock struct{}
+
+// TAINow returns TAINow()
+func (SystemClock) TAINow() TAI {
+	return TAINow()
+}
+
+// TAINNow returns TAINNow()
+func (SystemClock) TAINNow() TAIN {
+	return TAINNow()
+}
+
+// KernelClock is a Clock reading the kernel's CLOCK_TAI, falling back to
+// the system clock like KernelTAINNow does
+type KernelClock struct {
+	// OnFallback, if not nil, is called with the reason every time the
+	// clock falls back to the system clock
+	OnFallback func(error)
+}
+
+// TAINow returns the current timestamp in TAI struct
+func (c KernelClock) TAINow() TAI {
+	return TAIfromTAIN(c.TAINNow())
+}
+
+// TAINNow returns the current timestamp in TAIN struct
+func (c KernelClock) TAINNow() TAIN {
+	t, err := KernelTAINNow()
+	if err != nil && c.OnFallback != nil {
+		c.OnFallback(err)
+	}
+	return t
+}
+
+// FixedClock is a Clock that always returns the same timestamp
+type FixedClock TAIN
+
+// TAINow returns the fixed timestamp in TAI struct
+func (c FixedClock) TAINow() TAI {
+	return TAIfromTAIN(TAIN(c))
+}
+
+// TAINNow returns the fixed timestamp in TAIN struct
+func (c FixedClock) TAINNow() TAIN {
+	return TAIN(c)
+}
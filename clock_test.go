@@ -0,0 +1,72 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"sync"
+	"testing"
+)
+
+var (
+	_ Clock = SystemClock{}
+	_ Clock = KernelClock{}
+	_ Clock = FixedClock{}
+)
+
+func TestSystemClock(t *testing.T) {
+	var c Clock = SystemClock{}
+	before := TAINNow()
+	now := c.TAINNow()
+	if !tainLessOrEqual(before, now) || !tainLessOrEqual(now, TAINNow()) {
+		t.Errorf("SystemClock.TAINNow() = %v is not current", now)
+	}
+	if d := c.TAINow().x - before.sec; d > 1 {
+		t.Errorf("SystemClock.TAINow() is %d seconds off", d)
+	}
+}
+
+func TestKernelClockFallback(t *testing.T) {
+	var reason error
+	c := KernelClock{OnFallback: func(err error) { reason = err }}
+	_, err := KernelTAINNow()
+	c.TAINNow()
+	if (err == nil) != (reason == nil) {
+		t.Errorf("OnFallback got %v, KernelTAINNow failed with %v", reason, err)
+	}
+}
+
+func TestFixedClock(t *testing.T) {
+	tain := TAIN{sec: 0x400000005A848EAD, nano: 5}
+	c := FixedClock(tain)
+	if c.TAINNow() != tain {
+		t.Errorf("FixedClock.TAINNow() = %v", c.TAINNow())
+	}
+	if c.TAINow() != (TAI{x: 0x400000005A848EAD}) {
+		t.Errorf("FixedClock.TAINow() = %v", c.TAINow())
+	}
+}
+
+// steppingClock returns its readings in order, repeating the last one
+type steppingClock struct {
+	mu       sync.Mutex
+	readings []TAIN
+}
+
+func (c *steppingClock) TAINow() TAI {
+	return TAIfromTAIN(c.TAINNow())
+}
+
+func (c *steppingClock) TAINNow() TAIN {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.readings[0]
+	if len(c.readings) > 1 {
+		c.readings = c.readings[1:]
+	}
+	return t
+}
@@ -16,3 +16,24 @@ const TAILength = 8
 
 // TAINLength is the length of a TAIN timestamp in bytes
 const TAINLength = 12
+
+// TAILabelLength is the length of the ASCII label of a TAI timestamp
+const TAILabelLength = 1 + 2*TAILength
+
+// TAINLabelLength is the length of the ASCII label of a TAIN timestamp
+const TAINLabelLength = 1 + 2*TAINLength
+
+// HLCLength is the length of a hybrid logical clock timestamp in bytes
+const HLCLength = TAINLength + 4
+
+// IDLength is the length of an ID in bytes
+const IDLength = TAINLength + 8
+
+// NTPLength is the length of an NTP timestamp in bytes
+const NTPLength = 8
+
+// NTPDateLength is the length of an NTP datestamp in bytes
+const NTPDateLength = 16
+
+// PTPLength is the length of a PTP timestamp in bytes
+const PTPLength = 10
@@ -0,0 +1,93 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrLeapTableExpired is returned by the checked conversions when the UTC
+// time is past the expiry of the leap second table, the result may be off
+// by leap seconds announced since
+var ErrLeapTableExpired = errors.New("leap second table expired, UTC conversion is uncertain")
+
+// StaleLeapTableHook is called once per leap second table when the first
+// conversion past its expiry happens, with the table and the UTC time
+// converted
+type StaleLeapTableHook func(t *LeapTable, at time.Time)
+
+var staleHook atomic.Pointer[StaleLeapTableHook]
+
+// SetStaleLeapTableHook installs f to be called when a conversion first
+// needs a leap second table past its expiry, nil removes the hook. The hook
+// runs in the converting goroutine and should return quickly, for example
+//
+//	glibtai.SetStaleLeapTableHook(func(t *glibtai.LeapTable, at time.Time) {
+//		log.Printf("leap second table expired %v, converting %v", t.ValidUntil(), at)
+//	})
+func SetStaleLeapTableHook(f StaleLeapTableHook) {
+	if f == nil {
+		staleHook.Store(nil)
+		return
+	}
+	staleHook.Store(&f)
+}
+
+// ValidUntil returns when the current leap second table expires, or the
+// zero time if it does not
+func ValidUntil() time.Time {
+	return CurrentLeapTable().ValidUntil()
+}
+
+// checkExpiry calls the stale hook the first time t converts a Unix second
+// past its expiry
+func (t *LeapTable) checkExpiry(unix int64) {
+	if unix < t.expires || t.stale.Load() {
+		return
+	}
+	f := staleHook.Load()
+	if f != nil && t.stale.CompareAndSwap(false, true) {
+		(*f)(t, time.Unix(unix, 0).UTC())
+	}
+}
+
+// certain returns ErrLeapTableExpired if unix is past the expiry of t
+func (t *LeapTable) certain(unix int64) error {
+	if unix >= t.expires {
+		return ErrLeapTableExpired
+	}
+	return nil
+}
+
+// TAIfromTimeChecked is TAIfromTime, it also returns ErrLeapTableExpired
+// when t is past the expiry of the leap second table
+func TAIfromTimeChecked(t time.Time) (TAI, error) {
+	return TAIfromTime(t), CurrentLeapTable().certain(t.Unix())
+}
+
+// TAINfromTimeChecked is TAINfromTime, it also returns ErrLeapTableExpired
+// when t is past the expiry of the leap second table
+func TAINfromTimeChecked(t time.Time) (TAIN, error) {
+	return TAINfromTime(t), CurrentLeapTable().certain(t.Unix())
+}
+
+// TAITimeChecked is TAITime, it also returns ErrLeapTableExpired when the
+// result is past the expiry of the leap second table
+func TAITimeChecked(t TAI) (time.Time, error) {
+	tm := TAITime(t)
+	return tm, CurrentLeapTable().certain(tm.Unix())
+}
+
+// TAINTimeChecked is TAINTime, it also returns ErrLeapTableExpired when the
+// result is past the expiry of the leap second table
+func TAINTimeChecked(t TAIN) (time.Time, error) {
+	tm := TAINTime(t)
+	return tm, CurrentLeapTable().certain(tm.Unix())
+}
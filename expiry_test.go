@@ -0,0 +1,118 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karasz/glibtai/internal/leapgen"
+)
+
+func TestBuiltinValidUntil(t *testing.T) {
+	l, err := leapgen.Read("internal/leapgen/leap-seconds.list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := BuiltinLeapTable().ValidUntil(); !v.Equal(l.Expires) {
+		t.Errorf("built-in table is valid until %v, expected %v", v, l.Expires)
+	}
+	if v := ValidUntil(); !v.Equal(CurrentLeapTable().ValidUntil()) {
+		t.Errorf("ValidUntil() = %v", v)
+	}
+	if v := BuiltinLeapTable().WithValidUntil(time.Time{}).ValidUntil(); !v.IsZero() {
+		t.Errorf("table without expiry is valid until %v", v)
+	}
+}
+
+func TestCheckedConversions(t *testing.T) {
+	defer SetLeapTable(nil)
+	validUntil := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	SetLeapTable(BuiltinLeapTable().WithValidUntil(validUntil))
+
+	before := validUntil.Add(-time.Second)
+	if _, err := TAIfromTimeChecked(before); err != nil {
+		t.Errorf("TAIfromTimeChecked(%v) returned %v", before, err)
+	}
+	tain, err := TAINfromTimeChecked(before)
+	if err != nil {
+		t.Errorf("TAINfromTimeChecked(%v) returned %v", before, err)
+	}
+	if _, err := TAINTimeChecked(tain); err != nil {
+		t.Errorf("TAINTimeChecked(%v) returned %v", tain, err)
+	}
+
+	tai, err := TAIfromTimeChecked(validUntil)
+	if !errors.Is(err, ErrLeapTableExpired) || tai != TAIfromTime(validUntil) {
+		t.Errorf("TAIfromTimeChecked(%v) = %v, %v", validUntil, tai, err)
+	}
+	tain, err = TAINfromTimeChecked(validUntil)
+	if !errors.Is(err, ErrLeapTableExpired) {
+		t.Errorf("TAINfromTimeChecked(%v) returned %v", validUntil, err)
+	}
+	if tm, err := TAITimeChecked(tai); !errors.Is(err, ErrLeapTableExpired) || !tm.Equal(validUntil) {
+		t.Errorf("TAITimeChecked(%v) = %v, %v", tai, tm, err)
+	}
+	if tm, err := TAINTimeChecked(tain); !errors.Is(err, ErrLeapTableExpired) || !tm.Equal(validUntil) {
+		t.Errorf("TAINTimeChecked(%v) = %v, %v", tain, tm, err)
+	}
+
+	SetLeapTable(BuiltinLeapTable().WithValidUntil(time.Time{}))
+	if _, err := TAINfromTimeChecked(time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("table without expiry returned %v", err)
+	}
+}
+
+func TestStaleLeapTableHook(t *testing.T) {
+	defer SetLeapTable(nil)
+	defer SetStaleLeapTableHook(nil)
+
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var seen *LeapTable
+	var at time.Time
+	SetStaleLeapTableHook(func(lt *LeapTable, tm time.Time) {
+		calls.Add(1)
+		mu.Lock()
+		seen, at = lt, tm
+		mu.Unlock()
+	})
+
+	validUntil := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	table := BuiltinLeapTable().WithValidUntil(validUntil)
+	SetLeapTable(table)
+
+	TAINfromTime(validUntil.Add(-time.Second))
+	if calls.Load() != 0 {
+		t.Fatal("hook fired before the expiry")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			TAINfromTime(validUntil.Add(time.Hour))
+			TAITime(TAIfromTime(validUntil.Add(2 * time.Hour)))
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 || seen != table || !at.After(validUntil) {
+		t.Errorf("hook fired %d times, last for %v at %v", calls.Load(), seen, at)
+	}
+
+	// a new table fires again
+	SetLeapTable(table.WithValidUntil(validUntil))
+	TAINfromTime(validUntil)
+	if calls.Load() != 2 {
+		t.Errorf("hook fired %d times for two tables", calls.Load())
+	}
+}
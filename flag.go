@@ -0,0 +1,177 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TAINfromSpec returns a TAIN struct from a command line style timestamp:
+// an '@' TAI or TAIN label, an RFC 3339 time, Unix seconds with an
+// optional fraction, "now", or a signed duration relative to now such as
+// "-15m" or "now+1h"
+func TAINfromSpec(s string, now TAIN) (TAIN, error) {
+	switch {
+	case s == "":
+		return TAIN{}, fmt.Errorf("empty timestamp")
+	case s[0] == '@':
+		return tainFromLabel(s)
+	case s == "now":
+		return now, nil
+	case strings.HasPrefix(s, "now+"), strings.HasPrefix(s, "now-"):
+		return tainFromRelative(s[3:], now)
+	case len(s) >= 10 && s[4] == '-' && s[7] == '-':
+		return TAINParseRFC3339(s, RFC3339Lenient)
+	case isUnixSeconds(s):
+		return tainFromUnixSeconds(s)
+	default:
+		return tainFromRelative(s, now)
+	}
+}
+
+// TAIfromSpec returns a TAI struct from a command line style timestamp,
+// see TAINfromSpec for the accepted forms
+func TAIfromSpec(s string, now TAI) (TAI, error) {
+	t, err := TAINfromSpec(s, TAIN{sec: now.x})
+	return TAI{x: t.sec}, err
+}
+
+// tainFromLabel parses a TAI or a TAIN label
+func tainFromLabel(s string) (TAIN, error) {
+	switch len(s) {
+	case TAINLabelLength:
+		return TAINfromString(s)
+	case TAILabelLength:
+		t, err := TAIfromString(s)
+		return TAIN{sec: t.x}, err
+	default:
+		return TAIN{}, fmt.Errorf("TAI representation %s is not valid, it has the wrong length", s)
+	}
+}
+
+// tainFromRelative parses a signed duration relative to now
+func tainFromRelative(s string, now TAIN) (TAIN, error) {
+	if s == "" || (s[0] != '+' && s[0] != '-') {
+		return TAIN{}, fmt.Errorf("timestamp %q is not valid", s)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return TAIN{}, err
+	}
+	return TAINAdd(now, d), nil
+}
+
+// isUnixSeconds reports whether s looks like [-]seconds[.fraction]
+func isUnixSeconds(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	sec, frac, _ := strings.Cut(s, ".")
+	if sec == "" {
+		return false
+	}
+	for _, c := range []byte(sec + frac) {
+		if !isDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// tainFromUnixSeconds parses Unix seconds with an optional fraction
+func tainFromUnixSeconds(s string) (TAIN, error) {
+	sec, frac, _ := strings.Cut(s, ".")
+	x, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return TAIN{}, err
+	}
+
+	var nano int64
+	if frac != "" {
+		frac = (frac + "000000000")[:9]
+		nano, _ = strconv.ParseInt(frac, 10, 64)
+		if s[0] == '-' {
+			nano = -nano
+		}
+	}
+	return TAINfromTime(time.Unix(x, nano)), nil
+}
+
+// TAIValue is a flag.Value and encoding.TextUnmarshaler for TAI
+// timestamps, accepting everything TAIfromSpec does
+type TAIValue TAI
+
+// Set implements flag.Value
+func (v *TAIValue) Set(s string) error {
+	t, err := TAIfromSpec(s, TAINow())
+	if err != nil {
+		return err
+	}
+	*v = TAIValue(t)
+	return nil
+}
+
+func (v *TAIValue) String() string {
+	return TAI(*v).String()
+}
+
+// Get implements flag.Getter, it returns a TAI
+func (v *TAIValue) Get() any {
+	return TAI(*v)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (v *TAIValue) UnmarshalText(text []byte) error {
+	return v.Set(string(text))
+}
+
+// TAIVar defines a TAI flag with specified name, default value, and usage
+// string in fs. The argument p points to a TAI variable in which to store
+// the value of the flag.
+func TAIVar(fs *flag.FlagSet, p *TAI, name string, value TAI, usage string) {
+	*p = value
+	fs.Var((*TAIValue)(p), name, usage)
+}
+
+// TAINValue is a flag.Value and encoding.TextUnmarshaler for TAIN
+// timestamps, accepting everything TAINfromSpec does
+type TAINValue TAIN
+
+// Set implements flag.Value
+func (v *TAINValue) Set(s string) error {
+	t, err := TAINfromSpec(s, TAINNow())
+	if err != nil {
+		return err
+	}
+	*v = TAINValue(t)
+	return nil
+}
+
+func (v *TAINValue) String() string {
+	return TAIN(*v).String()
+}
+
+// Get implements flag.Getter, it returns a TAIN
+func (v *TAINValue) Get() any {
+	return TAIN(*v)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (v *TAINValue) UnmarshalText(text []byte) error {
+	return v.Set(string(text))
+}
+
+// TAINVar defines a TAIN flag with specified name, default value, and
+// usage string in fs. The argument p points to a TAIN variable in which to
+// store the value of the flag.
+func TAINVar(fs *flag.FlagSet, p *TAIN, name string, value TAIN, usage string) {
+	*p = value
+	fs.Var((*TAINValue)(p), name, usage)
+}
@@ -0,0 +1,109 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"encoding"
+	"flag"
+	"testing"
+	"time"
+)
+
+var (
+	_ flag.Getter              = (*TAINValue)(nil)
+	_ encoding.TextUnmarshaler = (*TAINValue)(nil)
+	_ flag.Getter              = (*TAIValue)(nil)
+	_ encoding.TextUnmarshaler = (*TAIValue)(nil)
+)
+
+func TestTAINfromSpec(t *testing.T) {
+	now := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	unix := TAINfromTime(time.Unix(1500000000, 250000000))
+	tests := []struct {
+		spec     string
+		expected TAIN
+	}{
+		{"now", now},
+		{"-15m", TAINAdd(now, -15*time.Minute)},
+		{"+1h30m", TAINAdd(now, 90*time.Minute)},
+		{"now-1s", TAINAdd(now, -time.Second)},
+		{"now+500ms", TAINAdd(now, 500*time.Millisecond)},
+		{"1500000000.25", unix},
+		{"1500000000", TAIN{sec: unix.sec}},
+		{"-1.5", TAINfromTime(time.Unix(-2, 500000000))},
+		{"2018-02-14T19:31:10Z", now},
+		{"2016-12-31T23:59:60Z", TAIN{sec: tai64Epoch + 1483228836}},
+		{"@400000005A848EAD00000005", TAIN{sec: 0x400000005A848EAD, nano: 5}},
+		{"@400000005A848EAD", TAIN{sec: 0x400000005A848EAD}},
+	}
+
+	for _, tc := range tests {
+		result, err := TAINfromSpec(tc.spec, now)
+		if err != nil {
+			t.Errorf("TAINfromSpec(%q) failed: %v", tc.spec, err)
+		} else if result != tc.expected {
+			t.Errorf("TAINfromSpec(%q) = %v, expected %v", tc.spec, result, tc.expected)
+		}
+	}
+}
+
+func TestTAINfromSpecErrors(t *testing.T) {
+	for _, spec := range []string{"", "@", "@4000", "later", "15m", "now-", "now*2", "1.5.5", "2018-02-14"} {
+		if result, err := TAINfromSpec(spec, TAIN{}); err == nil {
+			t.Errorf("TAINfromSpec(%q) = %v, expected an error", spec, result)
+		}
+	}
+}
+
+func TestTAIfromSpec(t *testing.T) {
+	now := TAIfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	result, err := TAIfromSpec("-1h", now)
+	if err != nil || result != TAIAdd(now, -time.Hour) {
+		t.Errorf("TAIfromSpec(-1h) = %v, %v", result, err)
+	}
+}
+
+func TestTAINVar(t *testing.T) {
+	var since, until TAIN
+	var at TAI
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	TAINVar(fs, &since, "since", TAIN{}, "start")
+	TAINVar(fs, &until, "until", TAIN{sec: 1}, "end")
+	TAIVar(fs, &at, "at", TAI{}, "instant")
+
+	before := TAINNow()
+	err := fs.Parse([]string{"-since", "-15m", "-at", "@400000005A848EAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tainLessOrEqual(TAINAdd(before, -15*time.Minute), since) || !tainLessOrEqual(since, TAINNow()) {
+		t.Errorf("-since -15m gave %v", since)
+	}
+	if until != (TAIN{sec: 1}) {
+		t.Errorf("-until default gave %v", until)
+	}
+	if at != (TAI{x: 0x400000005A848EAD}) {
+		t.Errorf("-at gave %v", at)
+	}
+	if err := fs.Set("at", "tomorrow"); err == nil {
+		t.Error("Setting -at to tomorrow did not fail")
+	}
+}
+
+func TestTAINValueUnmarshalText(t *testing.T) {
+	var v TAINValue
+	if err := v.UnmarshalText([]byte("2016-12-31T23:59:60.5Z")); err != nil {
+		t.Fatal(err)
+	}
+	if s := v.String(); s != "@40000000586846A41DCD6500" {
+		t.Errorf("UnmarshalText gave %s", s)
+	}
+	if v.Get() != TAIN(v) {
+		t.Errorf("Get() = %v", v.Get())
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Format implements fmt.Formatter. The %s and %v verbs print the label,
+// %q the quoted label, %d the TAI seconds since 1970-01-01 00:00:00 TAI,
+// %x and %X the label without the leading '@', %+v appends the UTC
+// calendar time, leap seconds included, to the label and %#v prints a Go
+// expression that evaluates to t.
+func (t TAI) Format(f fmt.State, verb rune) {
+	var buf [64]byte
+	b := t.AppendLabel(buf[:0])
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		b = fmt.Appendf(buf[:0], "glibtai.TAIUnpack(%#v)", TAIPack(t))
+	case verb == 'v' && f.Flag('+'):
+		b = TAIAppendFormat(append(b, " ("...), t, "2006-01-02 15:04:05 MST", ScaleUTC)
+		b = append(b, ')')
+	}
+	formatLabel(f, verb, b, t.x, "glibtai.TAI")
+}
+
+// Format implements fmt.Formatter. The %s and %v verbs print the label,
+// %q the quoted label, %d the TAI seconds since 1970-01-01 00:00:00 TAI,
+// %x and %X the label without the leading '@', %+v appends the UTC
+// calendar time, leap seconds included, to the label and %#v prints a Go
+// expression that evaluates to t.
+func (t TAIN) Format(f fmt.State, verb rune) {
+	var buf [96]byte
+	b := t.AppendLabel(buf[:0])
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		b = fmt.Appendf(buf[:0], "glibtai.TAINUnpack(%#v)", TAINPack(t))
+	case verb == 'v' && f.Flag('+'):
+		b = TAINAppendFormat(append(b, " ("...), t, "2006-01-02 15:04:05.999999999 MST", ScaleUTC)
+		b = append(b, ')')
+	}
+	formatLabel(f, verb, b, t.sec, "glibtai.TAIN")
+}
+
+// formatLabel writes the representation selected by verb given the
+// already rendered label (or %v variant) and the seconds part of the label
+func formatLabel(f fmt.State, verb rune, label []byte, sec uint64, name string) {
+	var buf [32]byte
+	out := label
+	switch verb {
+	case 'v', 's':
+	case 'q':
+		out = strconv.AppendQuote(buf[:0], string(label))
+	case 'd':
+		out = strconv.AppendInt(buf[:0], int64(sec-tai64Epoch), 10)
+	case 'X':
+		out = label[1:]
+	case 'x':
+		out = label[1:]
+		for i, c := range out {
+			if c >= 'A' && c <= 'F' {
+				out[i] = c + 'a' - 'A'
+			}
+		}
+	default:
+		out = fmt.Appendf(nil, "%%!%c(%s=%s)", verb, name, label)
+	}
+	writePadded(f, out)
+}
+
+// writePadded writes b honouring the width and '-' flag of f
+func writePadded(f fmt.State, b []byte) {
+	width, ok := f.Width()
+	if !ok || width <= len(b) {
+		_, _ = f.Write(b)
+		return
+	}
+
+	pad := make([]byte, width-len(b))
+	for i := range pad {
+		pad[i] = ' '
+	}
+	if f.Flag('-') {
+		_, _ = f.Write(b)
+		_, _ = f.Write(pad)
+	} else {
+		_, _ = f.Write(pad)
+		_, _ = f.Write(b)
+	}
+}
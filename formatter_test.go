@@ -0,0 +1,84 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTAIFormat(t *testing.T) {
+	tai := TAI{x: 0x40000000036DB755}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%v", "@40000000036DB755"},
+		{"%s", "@40000000036DB755"},
+		{"%q", `"@40000000036DB755"`},
+		{"%d", "57522005"},
+		{"%x", "40000000036db755"},
+		{"%X", "40000000036DB755"},
+		{"%+v", "@40000000036DB755 (1971-10-28 18:19:55 UTC)"},
+		{"%#v", "glibtai.TAIUnpack([]byte{0x40, 0x0, 0x0, 0x0, 0x3, 0x6d, 0xb7, 0x55})"},
+		{"%20s|", "   @40000000036DB755|"},
+		{"%-20s|", "@40000000036DB755   |"},
+		{"%z", "%!z(glibtai.TAI=@40000000036DB755)"},
+	}
+
+	for _, tc := range tests {
+		if s := fmt.Sprintf(tc.format, tai); s != tc.expected {
+			t.Errorf("Sprintf(%q) = %q, expected %q", tc.format, s, tc.expected)
+		}
+	}
+}
+
+func TestFormatSecondsBefore1970(t *testing.T) {
+	if s := fmt.Sprintf("%d", TAI{x: tai64Epoch - 5}); s != "-5" {
+		t.Errorf("Sprintf(%%d) = %q, expected -5", s)
+	}
+}
+
+func TestTAINFormat(t *testing.T) {
+	tain := TAIN{sec: 0x40000000036DB755, nano: 0x1DCD6500}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%v", "@40000000036DB7551DCD6500"},
+		{"%s", "@40000000036DB7551DCD6500"},
+		{"%d", "57522005"},
+		{"%x", "40000000036db7551dcd6500"},
+		{"%X", "40000000036DB7551DCD6500"},
+		{"%+v", "@40000000036DB7551DCD6500 (1971-10-28 18:19:55.5 UTC)"},
+		{"%#v", "glibtai.TAINUnpack([]byte{0x40, 0x0, 0x0, 0x0, 0x3, 0x6d, 0xb7, 0x55, 0x1d, 0xcd, 0x65, 0x0})"},
+		{"%z", "%!z(glibtai.TAIN=@40000000036DB7551DCD6500)"},
+	}
+
+	for _, tc := range tests {
+		if s := fmt.Sprintf(tc.format, tain); s != tc.expected {
+			t.Errorf("Sprintf(%q) = %q, expected %q", tc.format, s, tc.expected)
+		}
+	}
+}
+
+func TestFormatLeavesLabelIntact(t *testing.T) {
+	tain := TAIN{sec: 0x40000000036DB755, nano: 0xABCDEF12}
+	_ = fmt.Sprintf("%x", tain)
+	if s := tain.String(); s != "@40000000036DB755ABCDEF12" {
+		t.Errorf("String() after %%x = %s", s)
+	}
+}
+
+func TestTAINFormatLeapSecond(t *testing.T) {
+	tain := TAIN{sec: 0x40000000586846A4, nano: 500000000}
+	expected := "@40000000586846A41DCD6500 (2016-12-31 23:59:60.5 UTC)"
+	if s := fmt.Sprintf("%+v", tain); s != expected {
+		t.Errorf("Sprintf(%%+v) = %q, expected %q", s, expected)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"strconv"
+	"time"
+)
+
+// GNSS selects the time scale of a satellite navigation system. Methods
+// and functions taking a GNSS panic for values other than the constants
+// below, see Valid.
+type GNSS int
+
+const (
+	// GPS time is TAI - 19s and counts weeks from 1980-01-06
+	GPS GNSS = iota
+	// Galileo System Time shares the GPS offset and counts weeks from
+	// 1999-08-22, GPS week 1024
+	Galileo
+	// BeiDou time is TAI - 33s and counts weeks from 2006-01-01
+	BeiDou
+)
+
+// SecondsPerWeek is the length of a GNSS week
+const SecondsPerWeek = 7 * 86400
+
+// gnssEpochs are the labels of week 0 of every system, a UTC midnight
+// plus the TAI - UTC offset of the day, except for Galileo which starts
+// 13 seconds before midnight UTC at GPS week 1024
+var gnssEpochs = [...]uint64{
+	GPS:     tai64Epoch + 315964800 + 19,
+	Galileo: tai64Epoch + 315964800 + 19 + 1024*SecondsPerWeek,
+	BeiDou:  tai64Epoch + 1136073600 + 33,
+}
+
+// gnssOffsets are TAI minus the time of every system in seconds
+var gnssOffsets = [...]int64{GPS: 19, Galileo: 19, BeiDou: 33}
+
+func (g GNSS) String() string {
+	switch g {
+	case GPS:
+		return "GPS"
+	case Galileo:
+		return "Galileo"
+	case BeiDou:
+		return "BeiDou"
+	default:
+		return "GNSS(" + strconv.Itoa(int(g)) + ")"
+	}
+}
+
+// Valid reports whether g is one of the defined systems
+func (g GNSS) Valid() bool {
+	return g >= 0 && int(g) < len(gnssEpochs)
+}
+
+// check panics if g is not a defined system
+func (g GNSS) check() {
+	if !g.Valid() {
+		panic("glibtai: undefined " + g.String())
+	}
+}
+
+// Epoch returns the start of week 0 of g as a TAI timestamp
+func (g GNSS) Epoch() TAI {
+	g.check()
+	return TAI{x: gnssEpochs[g]}
+}
+
+// Offset returns TAI minus the time of g, a constant
+func (g GNSS) Offset() time.Duration {
+	g.check()
+	return time.Duration(gnssOffsets[g]) * time.Second
+}
+
+// GNSSWeekTime is a GNSS timestamp as a full week number and time of week
+type GNSSWeekTime struct {
+	Week int
+	TOW  time.Duration
+}
+
+// GNSSWeekTimefromTAIN returns the week and time of week of t in the time
+// scale of g. Times before the epoch have negative week numbers.
+func GNSSWeekTimefromTAIN(g GNSS, t TAIN) GNSSWeekTime {
+	g.check()
+	sec := int64(t.sec - gnssEpochs[g])
+	week := sec / SecondsPerWeek
+	tow := sec % SecondsPerWeek
+	if tow < 0 {
+		week--
+		tow += SecondsPerWeek
+	}
+	return GNSSWeekTime{
+		Week: int(week),
+		TOW:  time.Duration(tow)*time.Second + time.Duration(t.nano),
+	}
+}
+
+// TAINfromGNSSWeekTime returns the TAIN timestamp of a week and time of
+// week in the time scale of g. The time of week may be out of range, it
+// is added to the start of the week.
+func TAINfromGNSSWeekTime(g GNSS, wt GNSSWeekTime) TAIN {
+	g.check()
+	start := TAIN{sec: gnssEpochs[g] + uint64(int64(wt.Week)*SecondsPerWeek)}
+	return TAINAdd(start, wt.TOW)
+}
+
+// GNSSWeekTimefromTAI returns the week and time of week of t in the time
+// scale of g
+func GNSSWeekTimefromTAI(g GNSS, t TAI) GNSSWeekTime {
+	return GNSSWeekTimefromTAIN(g, TAIN{sec: t.x})
+}
+
+// TAIfromGNSSWeekTime returns the TAI timestamp of a week and time of week
+// in the time scale of g, truncated to the second
+func TAIfromGNSSWeekTime(g GNSS, wt GNSSWeekTime) TAI {
+	return TAIfromTAIN(TAINfromGNSSWeekTime(g, wt))
+}
+
+// GNSSTime returns t as calendar time in the time scale of g, which has
+// no leap seconds. The result is labelled UTC like TAITime but is offset
+// from UTC by the leap seconds since the epoch of g.
+func GNSSTime(g GNSS, t TAIN) time.Time {
+	g.check()
+	sec := int64(t.sec-tai64Epoch) - gnssOffsets[g]
+	return time.Unix(sec, int64(t.nano)).UTC()
+}
+
+// TAINfromGNSSTime is the inverse of GNSSTime, it reads the calendar time
+// of tm as a time in the time scale of g
+func TAINfromGNSSTime(g GNSS, tm time.Time) TAIN {
+	g.check()
+	sec := tm.Unix() + gnssOffsets[g]
+	return TAIN{sec: tai64Epoch + uint64(sec), nano: uint32(tm.Nanosecond())}
+}
+
+// ResolveWeek returns the full week number of g whose low bits equal the
+// broadcast week, closest to the week of ref. Legacy GPS navigation
+// messages broadcast 10 bits, Galileo 12 and GPS CNAV and BeiDou 13.
+func (g GNSS) ResolveWeek(week, bits int, ref TAIN) int {
+	period := 1 << bits
+	refWeek := GNSSWeekTimefromTAIN(g, ref).Week
+	full := refWeek - floorMod(refWeek, period) + floorMod(week, period)
+	switch {
+	case full-refWeek > period/2:
+		full -= period
+	case refWeek-full >= period/2:
+		full += period
+	}
+	return full
+}
+
+// floorMod returns a modulo n in [0, n)
+func floorMod(a, n int) int {
+	m := a % n
+	if m < 0 {
+		m += n
+	}
+	return m
+}
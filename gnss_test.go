@@ -0,0 +1,146 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGNSSEpochs(t *testing.T) {
+	tests := []struct {
+		g     GNSS
+		epoch time.Time
+	}{
+		{GPS, time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)},
+		{Galileo, time.Date(1999, time.August, 21, 23, 59, 47, 0, time.UTC)},
+		{BeiDou, time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if e := TAIfromTime(tt.epoch); tt.g.Epoch() != e {
+			t.Errorf("%v epoch is %v, expected %v", tt.g, tt.g.Epoch(), e)
+		}
+		wt := GNSSWeekTimefromTAIN(tt.g, TAINfromTime(tt.epoch))
+		if wt != (GNSSWeekTime{}) {
+			t.Errorf("%v epoch is %+v", tt.g, wt)
+		}
+	}
+}
+
+func TestGNSSUndefined(t *testing.T) {
+	for _, g := range []GNSS{GPS, Galileo, BeiDou} {
+		if !g.Valid() {
+			t.Errorf("%v is not valid", g)
+		}
+	}
+	for _, g := range []GNSS{-1, 3, 5} {
+		if g.Valid() {
+			t.Errorf("%v is valid", g)
+		}
+		for name, f := range map[string]func(){
+			"Epoch":                func() { g.Epoch() },
+			"Offset":               func() { g.Offset() },
+			"GNSSWeekTimefromTAIN": func() { GNSSWeekTimefromTAIN(g, TAINNow()) },
+			"TAINfromGNSSTime":     func() { TAINfromGNSSTime(g, time.Now()) },
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r != "glibtai: undefined "+g.String() {
+						t.Errorf("%s(%v) recovered %v", name, g, r)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+}
+
+func TestGNSSWeekTime(t *testing.T) {
+	utc := time.Date(2018, time.February, 14, 19, 31, 10, 500, time.UTC)
+	tain := TAINfromTime(utc)
+	tests := []struct {
+		g  GNSS
+		wt GNSSWeekTime
+	}{
+		{GPS, GNSSWeekTime{1988, 329488*time.Second + 500}},
+		{Galileo, GNSSWeekTime{964, 329488*time.Second + 500}},
+		{BeiDou, GNSSWeekTime{632, 329474*time.Second + 500}},
+	}
+	for _, tt := range tests {
+		if wt := GNSSWeekTimefromTAIN(tt.g, tain); wt != tt.wt {
+			t.Errorf("%v week time is %+v, expected %+v", tt.g, wt, tt.wt)
+		}
+		if x := TAINfromGNSSWeekTime(tt.g, tt.wt); x != tain {
+			t.Errorf("%v %+v is %v, expected %v", tt.g, tt.wt, x, tain)
+		}
+		if x := TAIfromGNSSWeekTime(tt.g, GNSSWeekTimefromTAI(tt.g, TAIfromTAIN(tain))); x != TAIfromTAIN(tain) {
+			t.Errorf("%v TAI round trip returned %v", tt.g, x)
+		}
+	}
+}
+
+func TestGNSSWeekTimeBeforeEpoch(t *testing.T) {
+	tain := TAINAdd(TAINfromTime(time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)), -time.Second)
+	wt := GNSSWeekTimefromTAIN(GPS, tain)
+	if wt.Week != -1 || wt.TOW != (SecondsPerWeek-1)*time.Second {
+		t.Errorf("second before the GPS epoch is %+v", wt)
+	}
+	if x := TAINfromGNSSWeekTime(GPS, wt); x != tain {
+		t.Errorf("%+v is %v, expected %v", wt, x, tain)
+	}
+}
+
+func TestGNSSTime(t *testing.T) {
+	utc := time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC)
+	tain := TAINfromTime(utc)
+	if gps := GNSSTime(GPS, tain); !gps.Equal(utc.Add(18 * time.Second)) {
+		t.Errorf("GPS time is %v", gps)
+	}
+	if bdt := GNSSTime(BeiDou, tain); !bdt.Equal(utc.Add(4 * time.Second)) {
+		t.Errorf("BeiDou time is %v", bdt)
+	}
+	for _, g := range []GNSS{GPS, Galileo, BeiDou} {
+		if x := TAINfromGNSSTime(g, GNSSTime(g, tain)); x != tain {
+			t.Errorf("%v round trip returned %v", g, x)
+		}
+	}
+}
+
+func TestGNSSResolveWeek(t *testing.T) {
+	ref := func(y int, m time.Month, d int) TAIN {
+		return TAINfromTime(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	}
+	tests := []struct {
+		g    GNSS
+		week int
+		bits int
+		ref  TAIN
+		full int
+	}{
+		{GPS, 964, 10, ref(2018, time.February, 14), 1988},
+		{GPS, 0, 10, ref(2019, time.April, 10), 2048},
+		{GPS, 1023, 10, ref(2019, time.April, 10), 2047},
+		{GPS, 1, 10, ref(2019, time.March, 30), 2049},
+		{GPS, 1988, 13, ref(2018, time.February, 14), 1988},
+		{Galileo, 964, 12, ref(2018, time.February, 14), 964},
+		{BeiDou, 632, 13, ref(2030, time.January, 1), 632},
+	}
+	for _, tt := range tests {
+		if full := tt.g.ResolveWeek(tt.week, tt.bits, tt.ref); full != tt.full {
+			t.Errorf("%v week %d/%d bits resolved to %d, expected %d", tt.g, tt.week, tt.bits, full, tt.full)
+		}
+	}
+}
+
+func TestGNSSString(t *testing.T) {
+	for g, s := range map[GNSS]string{GPS: "GPS", Galileo: "Galileo", BeiDou: "BeiDou", 7: "GNSS(7)"} {
+		if g.String() != s {
+			t.Errorf("%d.String() = %s", int(g), g.String())
+		}
+	}
+}
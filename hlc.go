@@ -0,0 +1,187 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrHLCSkew is returned when a remote timestamp is too far ahead of
+	// the local clock
+	ErrHLCSkew = errors.New("remote timestamp exceeds the maximum clock skew")
+	// ErrHLCOverflow is returned when a remote timestamp would overflow
+	// the logical counter
+	ErrHLCOverflow = errors.New("logical counter overflow")
+)
+
+// HLCTimestamp is a hybrid logical clock timestamp, a TAIN physical time
+// and a logical counter ordering events within the same physical time
+type HLCTimestamp struct {
+	Wall    TAIN
+	Logical uint32
+}
+
+// HLCCompare returns -1, 0 or +1 depending on whether a is before, equal
+// to or after b
+func HLCCompare(a, b HLCTimestamp) int {
+	if c := tainCompare(a.Wall, b.Wall); c != 0 {
+		return c
+	}
+	switch {
+	case a.Logical < b.Logical:
+		return -1
+	case a.Logical > b.Logical:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// HLCPack packs a HLC timestamp in a byte array of size HLCLength, the
+// TAINPack form followed by the big endian logical counter
+func HLCPack(t HLCTimestamp) []byte {
+	result := make([]byte, HLCLength)
+	binary.BigEndian.PutUint64(result[:], t.Wall.sec)
+	binary.BigEndian.PutUint32(result[TAILength:], t.Wall.nano)
+	binary.BigEndian.PutUint32(result[TAINLength:], t.Logical)
+	return result
+}
+
+// HLCUnpack unpacks a HLC timestamp from a byte array of size HLCLength
+func HLCUnpack(s []byte) HLCTimestamp {
+	return HLCTimestamp{
+		Wall:    TAINUnpack(s),
+		Logical: binary.BigEndian.Uint32(s[TAINLength:]),
+	}
+}
+
+func (t HLCTimestamp) String() string {
+	var buf [1 + 2*HLCLength]byte
+	label := t.Wall.Label()
+	copy(buf[:], label[:])
+	putHex32(buf[TAINLabelLength:], t.Logical)
+	return string(buf[:])
+}
+
+// HLCfromString returns a HLC timestamp from its ASCII representation
+func HLCfromString(str string) (HLCTimestamp, error) {
+	if len(str) != 1+2*HLCLength || str[0] != '@' {
+		return HLCTimestamp{}, fmt.Errorf("HLC representation %s is not valid", str)
+	}
+
+	buf, err := hex.DecodeString(str[1:])
+	if err != nil {
+		return HLCTimestamp{}, err
+	}
+	return HLCUnpack(buf), nil
+}
+
+// HLC is a hybrid logical clock using a Clock for its physical time.
+// Its timestamps respect causality across machines while staying close to
+// TAI. It is safe for concurrent use.
+//
+// The zero value reads SystemClock and accepts any skew.
+type HLC struct {
+	clock   Clock
+	maxSkew time.Duration
+
+	mu   sync.Mutex
+	last HLCTimestamp
+}
+
+// NewHLC returns a hybrid logical clock reading c, or SystemClock if c is
+// nil. Remote timestamps more than maxSkew ahead of c are rejected, 0
+// accepts any skew.
+func NewHLC(c Clock, maxSkew time.Duration) *HLC {
+	return &HLC{clock: c, maxSkew: maxSkew}
+}
+
+// Now returns a timestamp for a local or send event, it is greater than
+// every timestamp returned or received before. When the logical counter
+// is exhausted Now waits for the physical clock to pass the last
+// timestamp, which never happens with a FixedClock or a clock that does
+// not advance; use TryNow where that matters.
+func (h *HLC) Now() HLCTimestamp {
+	for {
+		ts, err := h.TryNow()
+		if err == nil {
+			return ts
+		}
+		time.Sleep(time.Microsecond)
+	}
+}
+
+// TryNow is like Now but returns an error wrapping ErrHLCOverflow instead
+// of waiting when the logical counter is exhausted
+func (h *HLC) TryNow() (HLCTimestamp, error) {
+	pt := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case tainCompare(pt, h.last.Wall) > 0:
+		h.last = HLCTimestamp{Wall: pt}
+	case h.last.Logical < math.MaxUint32:
+		h.last.Logical++
+	default:
+		return HLCTimestamp{}, fmt.Errorf("%w: at %v", ErrHLCOverflow, h.last)
+	}
+	return h.last, nil
+}
+
+// now reads the physical clock of h
+func (h *HLC) now() TAIN {
+	if h.clock == nil {
+		return TAINNow()
+	}
+	return h.clock.TAINNow()
+}
+
+// Update merges a timestamp received from a remote clock and returns the
+// timestamp of the receive event. It returns an error wrapping ErrHLCSkew
+// and leaves the clock alone if remote is too far ahead, or ErrHLCOverflow
+// if the logical counter of the receive event would not fit.
+func (h *HLC) Update(remote HLCTimestamp) (HLCTimestamp, error) {
+	pt := h.now()
+	if h.maxSkew > 0 && tainCompare(remote.Wall, TAINAdd(pt, h.maxSkew)) > 0 {
+		return HLCTimestamp{}, fmt.Errorf("%w: %v is ahead of %v", ErrHLCSkew, remote, pt)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	last := h.last
+	wall := last.Wall
+	if tainCompare(remote.Wall, wall) > 0 {
+		wall = remote.Wall
+	}
+
+	var logical uint32
+	switch {
+	case tainCompare(pt, wall) > 0:
+		h.last = HLCTimestamp{Wall: pt}
+		return h.last, nil
+	case wall == last.Wall && wall == remote.Wall:
+		logical = max(last.Logical, remote.Logical)
+	case wall == last.Wall:
+		logical = last.Logical
+	default:
+		logical = remote.Logical
+	}
+	if logical == math.MaxUint32 {
+		return HLCTimestamp{}, fmt.Errorf("%w: receiving %v at %v", ErrHLCOverflow, remote, last)
+	}
+	h.last = HLCTimestamp{Wall: wall, Logical: logical + 1}
+	return h.last, nil
+}
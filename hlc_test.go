@@ -0,0 +1,160 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHLCNow(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	clock := &steppingClock{readings: []TAIN{base, base, TAINAdd(base, -time.Second), TAINAdd(base, time.Second)}}
+	h := NewHLC(clock, 0)
+
+	expected := []HLCTimestamp{
+		{Wall: base},
+		{Wall: base, Logical: 1},
+		{Wall: base, Logical: 2},
+		{Wall: TAINAdd(base, time.Second)},
+	}
+	for i, e := range expected {
+		if ts := h.Now(); ts != e {
+			t.Errorf("call %d returned %v, expected %v", i, ts, e)
+		}
+	}
+}
+
+func TestHLCUpdate(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	ahead := TAINAdd(base, 500*time.Millisecond)
+	h := NewHLC(FixedClock(base), time.Second)
+
+	tests := []struct {
+		name     string
+		remote   HLCTimestamp
+		expected HLCTimestamp
+	}{
+		{"remote behind", HLCTimestamp{Wall: TAINAdd(base, -time.Second), Logical: 9}, HLCTimestamp{Wall: base}},
+		{"same wall", HLCTimestamp{Wall: base, Logical: 5}, HLCTimestamp{Wall: base, Logical: 6}},
+		{"remote ahead", HLCTimestamp{Wall: ahead, Logical: 3}, HLCTimestamp{Wall: ahead, Logical: 4}},
+		{"local ahead", HLCTimestamp{Wall: base, Logical: 100}, HLCTimestamp{Wall: ahead, Logical: 5}},
+		{"equal walls", HLCTimestamp{Wall: ahead, Logical: 10}, HLCTimestamp{Wall: ahead, Logical: 11}},
+	}
+
+	for _, tc := range tests {
+		ts, err := h.Update(tc.remote)
+		if err != nil || ts != tc.expected {
+			t.Errorf("%s: Update(%v) = %v, %v, expected %v", tc.name, tc.remote, ts, err, tc.expected)
+		}
+	}
+
+	last := h.Now()
+	_, err := h.Update(HLCTimestamp{Wall: TAINAdd(base, 2*time.Second)})
+	if !errors.Is(err, ErrHLCSkew) {
+		t.Errorf("Update of a skewed timestamp returned %v", err)
+	}
+	if next := h.Now(); HLCCompare(next, last) <= 0 || next.Wall != last.Wall {
+		t.Errorf("rejected update moved the clock: %v after %v", next, last)
+	}
+}
+
+func TestHLCNilClock(t *testing.T) {
+	for _, h := range []*HLC{{}, NewHLC(nil, 0)} {
+		before := TAINNow()
+		ts := h.Now()
+		if tainCompare(ts.Wall, before) < 0 {
+			t.Errorf("Now() = %v, before %v", ts, before)
+		}
+		if next, err := h.Update(ts); err != nil || HLCCompare(next, ts) <= 0 {
+			t.Errorf("Update(%v) = %v, %v", ts, next, err)
+		}
+	}
+}
+
+func TestHLCOverflow(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	full := HLCTimestamp{Wall: base, Logical: math.MaxUint32}
+
+	// Now waits for the clock to move on
+	clock := &steppingClock{readings: []TAIN{base, base, TAINAdd(base, time.Nanosecond)}}
+	h := NewHLC(clock, 0)
+	h.last = full
+	if ts := h.Now(); ts != (HLCTimestamp{Wall: TAINAdd(base, time.Nanosecond)}) {
+		t.Errorf("Now() after an exhausted counter = %v", ts)
+	}
+
+	// TryNow does not wait for a clock that never moves on
+	h = NewHLC(FixedClock(base), 0)
+	h.last = HLCTimestamp{Wall: base, Logical: math.MaxUint32 - 1}
+	if ts, err := h.TryNow(); err != nil || ts != full {
+		t.Errorf("TryNow() = %v, %v, expected %v", ts, err, full)
+	}
+	if ts, err := h.TryNow(); !errors.Is(err, ErrHLCOverflow) {
+		t.Errorf("TryNow() after an exhausted counter = %v, %v", ts, err)
+	}
+	if h.last != full {
+		t.Errorf("failed TryNow moved the clock to %v", h.last)
+	}
+
+	for _, last := range []HLCTimestamp{full, {Wall: base}} {
+		h = NewHLC(FixedClock(base), 0)
+		h.last = last
+		if _, err := h.Update(full); !errors.Is(err, ErrHLCOverflow) {
+			t.Errorf("Update(%v) after %v returned %v", full, last, err)
+		}
+		if h.last != last {
+			t.Errorf("failed update moved the clock to %v", h.last)
+		}
+	}
+}
+
+func TestHLCEncoding(t *testing.T) {
+	ts := HLCTimestamp{Wall: TAIN{sec: 0x400000005A848EAD, nano: 5}, Logical: 0xABCD}
+	s := ts.String()
+	if s != "@400000005A848EAD000000050000ABCD" {
+		t.Errorf("String() = %s", s)
+	}
+	back, err := HLCfromString(s)
+	if err != nil || back != ts {
+		t.Errorf("HLCfromString(%s) = %v, %v", s, back, err)
+	}
+	packed := HLCPack(ts)
+	if len(packed) != HLCLength || HLCUnpack(packed) != ts {
+		t.Errorf("HLCPack(%v) = %x does not round trip", ts, packed)
+	}
+	if string(packed[:TAINLength]) != string(TAINPack(ts.Wall)) {
+		t.Errorf("HLCPack(%v) = %x does not extend TAINPack", ts, packed)
+	}
+	for _, bad := range []string{"", "@400000005A848EAD00000005", "400000005A848EAD000000050000ABCD0", "@400000005A848EAD000000050000ABCZ"} {
+		if _, err := HLCfromString(bad); err == nil {
+			t.Errorf("HLCfromString(%q) did not fail", bad)
+		}
+	}
+}
+
+func TestHLCCompare(t *testing.T) {
+	a := HLCTimestamp{Wall: TAIN{sec: 10, nano: 5}, Logical: 1}
+	tests := []struct {
+		b        HLCTimestamp
+		expected int
+	}{
+		{a, 0},
+		{HLCTimestamp{Wall: a.Wall, Logical: 2}, -1},
+		{HLCTimestamp{Wall: a.Wall}, 1},
+		{HLCTimestamp{Wall: TAIN{sec: 10, nano: 6}}, -1},
+		{HLCTimestamp{Wall: TAIN{sec: 9, nano: 999999999}, Logical: 7}, 1},
+	}
+	for _, tc := range tests {
+		if c := HLCCompare(a, tc.b); c != tc.expected {
+			t.Errorf("HLCCompare(%v, %v) = %d, expected %d", a, tc.b, c, tc.expected)
+		}
+	}
+}
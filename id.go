@@ -0,0 +1,197 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// crockford is the Crockford base32 alphabet, in ASCII order so encoded
+// IDs sort like the IDs themselves
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// idTextLength is the length of the base32 representation of an ID
+const idTextLength = IDLength * 8 / 5
+
+// ID is a unique identifier that sorts by time: the TAINPack form of its
+// TAIN timestamp followed by 8 bytes of entropy. Comparing IDs or their
+// base32 strings lexicographically orders them by TAI.
+type ID [IDLength]byte
+
+// TAIN returns the timestamp of the ID
+func (id ID) TAIN() TAIN {
+	return TAINUnpack(id[:])
+}
+
+// String returns the 32 character Crockford base32 representation of id
+func (id ID) String() string {
+	var buf [idTextLength]byte
+	for g := 0; g < IDLength/5; g++ {
+		var v uint64
+		for _, b := range id[5*g : 5*g+5] {
+			v = v<<8 | uint64(b)
+		}
+		for i := 7; i >= 0; i-- {
+			buf[8*g+i] = crockford[v&0x1F]
+			v >>= 5
+		}
+	}
+	return string(buf[:])
+}
+
+// Hex returns the '@' prefixed hexadecimal representation of id, which
+// starts with the label of its timestamp
+func (id ID) Hex() string {
+	var buf [1 + 2*IDLength]byte
+	label := id.TAIN().Label()
+	copy(buf[:], label[:])
+	putHex64(buf[TAINLabelLength:], binary.BigEndian.Uint64(id[TAINLength:]))
+	return string(buf[:])
+}
+
+// MarshalText implements encoding.TextMarshaler using the base32 form
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, see IDfromString
+func (id *ID) UnmarshalText(text []byte) error {
+	x, err := IDfromString(string(text))
+	if err != nil {
+		return err
+	}
+	*id = x
+	return nil
+}
+
+// IDCompare returns -1, 0 or +1 depending on whether a sorts before,
+// equal to or after b
+func IDCompare(a, b ID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// IDfromString returns an ID from its base32 or '@' hexadecimal
+// representation. Base32 is decoded case insensitively and accepts I, L
+// and O for 1, 1 and 0 as Crockford's encoding does.
+func IDfromString(str string) (ID, error) {
+	var id ID
+	switch {
+	case len(str) == 1+2*IDLength && str[0] == '@':
+		_, err := hex.Decode(id[:], []byte(str[1:]))
+		return id, err
+	case len(str) == idTextLength:
+		return idFromBase32(str)
+	default:
+		return id, fmt.Errorf("ID representation %s is not valid, it has the wrong length", str)
+	}
+}
+
+// idFromBase32 decodes the base32 representation of an ID
+func idFromBase32(str string) (ID, error) {
+	var id ID
+	for g := 0; g < IDLength/5; g++ {
+		var v uint64
+		for _, c := range []byte(str[8*g : 8*g+8]) {
+			d := crockfordValue(c)
+			if d < 0 {
+				return ID{}, fmt.Errorf("ID representation %s is not valid, %q is not a base32 digit", str, c)
+			}
+			v = v<<5 | uint64(d)
+		}
+		for i := 4; i >= 0; i-- {
+			id[5*g+i] = byte(v)
+			v >>= 8
+		}
+	}
+	return id, nil
+}
+
+// crockfordValue returns the value of a base32 digit, or -1
+func crockfordValue(c byte) int {
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	for i := 0; i < len(crockford); i++ {
+		if crockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// IDGenerator creates strictly increasing IDs, safe for concurrent use.
+// IDs created while its clock does not advance, or after it was stepped
+// back, keep the last timestamp and increment the entropy instead.
+//
+// The zero value reads SystemClock and crypto/rand.
+type IDGenerator struct {
+	clock Clock
+	rand  io.Reader
+
+	mu   sync.Mutex
+	last ID
+}
+
+// NewIDGenerator returns an IDGenerator reading timestamps from c, or
+// SystemClock if c is nil, and entropy from r, crypto/rand if r is nil
+func NewIDGenerator(c Clock, r io.Reader) *IDGenerator {
+	return &IDGenerator{clock: c, rand: r}
+}
+
+// New returns an ID greater than every ID g returned before
+func (g *IDGenerator) New() (ID, error) {
+	var now TAIN
+	if g.clock == nil {
+		now = TAINNow()
+	} else {
+		now = g.clock.TAINNow()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last := g.last.TAIN()
+	if tainCompare(now, last) <= 0 {
+		if entropy := binary.BigEndian.Uint64(g.last[TAINLength:]); entropy < 1<<64-1 {
+			binary.BigEndian.PutUint64(g.last[TAINLength:], entropy+1)
+			return g.last, nil
+		}
+		now = tainFromNanos(tainNanos(last) + 1)
+	}
+
+	r := g.rand
+	if r == nil {
+		r = rand.Reader
+	}
+	var id ID
+	copy(id[:], TAINPack(now))
+	if _, err := io.ReadFull(r, id[TAINLength:]); err != nil {
+		return ID{}, err
+	}
+	g.last = id
+	return id, nil
+}
+
+var defaultIDGenerator = NewIDGenerator(SystemClock{}, nil)
+
+// NewID returns a new ID from a generator reading the system clock and
+// crypto/rand
+func NewID() (ID, error) {
+	return defaultIDGenerator.New()
+}
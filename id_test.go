@@ -0,0 +1,204 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// errReader fails every read
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestIDEncodings(t *testing.T) {
+	var id ID
+	copy(id[:], TAINPack(TAINUnpack([]byte{0x40, 0, 0, 0, 0x5a, 0x84, 0x8e, 0xad, 0, 0, 0, 5})))
+	copy(id[TAINLength:], []byte{0xde, 0xad, 0xbe, 0xef, 0, 1, 2, 3})
+
+	if h := id.Hex(); h != "@400000005A848EAD00000005DEADBEEF00010203" {
+		t.Errorf("Hex() = %s", h)
+	}
+	s := id.String()
+	if len(s) != 32 {
+		t.Fatalf("String() = %s, expected 32 characters", s)
+	}
+	for _, str := range []string{s, strings.ToLower(s), id.Hex()} {
+		x, err := IDfromString(str)
+		if err != nil || x != id {
+			t.Errorf("IDfromString(%s) = %v, %v", str, x, err)
+		}
+	}
+	if id.TAIN() != TAINUnpack(id[:TAINLength]) {
+		t.Errorf("TAIN() = %v", id.TAIN())
+	}
+}
+
+func TestIDCrockfordAliases(t *testing.T) {
+	x, err := IDfromString("0000000000000000000000000000000O")
+	y, _ := IDfromString("o0000000000000000000000000000000")
+	if err != nil || x != (ID{}) || y != (ID{}) {
+		t.Errorf("O is not read as 0: %v %v %v", x, y, err)
+	}
+	a, _ := IDfromString("0000000000000000000000000000000I")
+	b, _ := IDfromString("0000000000000000000000000000000l")
+	if a[IDLength-1] != 1 || b != a {
+		t.Errorf("I and l are not read as 1: %v %v", a, b)
+	}
+}
+
+func TestIDfromStringErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"0000000000000000000000000000000",
+		"000000000000000000000000000000U0",
+		"@400000005A848EAD00000005DEADBEEF0001020",
+		"@400000005A848EAD00000005DEADBEEF000102XY",
+	} {
+		if _, err := IDfromString(s); err == nil {
+			t.Errorf("IDfromString(%q) succeeded", s)
+		}
+	}
+}
+
+func TestIDText(t *testing.T) {
+	id, err := NewID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := id.MarshalText()
+	var x ID
+	if err := x.UnmarshalText(text); err != nil || x != id {
+		t.Errorf("UnmarshalText(%s) = %v, %v", text, x, err)
+	}
+	if err := x.UnmarshalText([]byte("bad")); err == nil {
+		t.Errorf("UnmarshalText accepted bad input")
+	}
+}
+
+func TestIDSortsLikeString(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	var ids []ID
+	for i := 0; i < 64; i++ {
+		var id ID
+		copy(id[:], TAINPack(TAINAdd(base, time.Duration(i*i*7919)%time.Second)))
+		id[IDLength-1] = byte(i * 37)
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	for i := 1; i < len(ids); i++ {
+		if IDCompare(ids[i-1], ids[i]) > 0 {
+			t.Fatalf("%s sorts before %s", ids[i-1], ids[i])
+		}
+	}
+}
+
+func TestIDGeneratorMonotonic(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	clock := &steppingClock{readings: []TAIN{
+		base,
+		base,
+		TAINAdd(base, -time.Second),
+		TAINAdd(base, time.Second),
+	}}
+	g := NewIDGenerator(clock, bytes.NewReader(make([]byte, 16)))
+
+	var prev ID
+	for i, e := range []TAIN{base, base, base, TAINAdd(base, time.Second)} {
+		id, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.TAIN() != e {
+			t.Errorf("call %d has timestamp %v, expected %v", i, id.TAIN(), e)
+		}
+		if i > 0 && IDCompare(prev, id) >= 0 {
+			t.Errorf("call %d returned %s, not after %s", i, id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestIDGeneratorEntropyOverflow(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	entropy := bytes.Repeat([]byte{0xff}, 8)
+	g := NewIDGenerator(FixedClock(base), bytes.NewReader(append(entropy, make([]byte, 8)...)))
+
+	first, _ := g.New()
+	second, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.TAIN() != TAINAdd(base, time.Nanosecond) || IDCompare(first, second) >= 0 {
+		t.Errorf("overflow returned %s after %s", second.Hex(), first.Hex())
+	}
+}
+
+func TestIDGeneratorError(t *testing.T) {
+	g := NewIDGenerator(SystemClock{}, errReader{})
+	if _, err := g.New(); err == nil {
+		t.Errorf("New succeeded without entropy")
+	}
+}
+
+func TestIDGeneratorZeroValue(t *testing.T) {
+	for _, g := range []*IDGenerator{{}, NewIDGenerator(nil, nil)} {
+		before := TAINNow()
+		a, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := g.New()
+		if err != nil || IDCompare(a, b) >= 0 || tainCompare(a.TAIN(), before) < 0 {
+			t.Errorf("New() = %v then %v, %v", a, b, err)
+		}
+	}
+}
+
+func TestIDGeneratorConcurrent(t *testing.T) {
+	g := NewIDGenerator(FixedClock(TAINNow()), nil)
+	const workers, each = 8, 200
+	results := make([][]ID, workers)
+	var wg sync.WaitGroup
+	for w := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				id, err := g.New()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				results[w] = append(results[w], id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool)
+	for _, ids := range results {
+		for i, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %s", id)
+			}
+			seen[id] = true
+			if i > 0 && IDCompare(ids[i-1], id) >= 0 {
+				t.Fatalf("%s not after %s", id, ids[i-1])
+			}
+		}
+	}
+}
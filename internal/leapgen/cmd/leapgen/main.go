@@ -0,0 +1,44 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Command leapgen writes the built-in leap second table of glibtai from
+// a leap-seconds.list file. It is run by go generate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/karasz/glibtai/internal/leapgen"
+)
+
+func main() {
+	in := flag.String("in", "internal/leapgen/leap-seconds.list", "leap-seconds.list to read")
+	out := flag.String("out", "leapsecs_table.go", "Go file to write")
+	name := flag.String("name", "", "list name in the generated header, the base name of -in if empty")
+	flag.Parse()
+
+	if *name == "" {
+		*name = filepath.Base(*in)
+	}
+	if err := run(*in, *out, *name); err != nil {
+		fmt.Fprintln(os.Stderr, "leapgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, name string) error {
+	l, err := leapgen.Read(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	src, err := l.Source(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
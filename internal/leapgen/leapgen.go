@@ -0,0 +1,219 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package leapgen generates the built-in leap second table of glibtai
+// from an IERS/NIST leap-seconds.list file.
+package leapgen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" //nolint:gosec // the file is hashed with SHA-1
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ntpUnix is the NTP second of 1970-01-01 00:00:00 UTC
+const ntpUnix = 2208988800
+
+// baseOffset is the TAI-UTC offset of the first line, which glibtai
+// assumes before its first leap second
+const baseOffset = 10
+
+var (
+	// ErrNoHash is returned when a list has no #h line
+	ErrNoHash = errors.New("leap-seconds.list has no hash")
+	// ErrBadHash is returned when the #h line does not match the contents
+	ErrBadHash = errors.New("leap-seconds.list hash mismatch")
+)
+
+// Leap is a line of the list: TAI-UTC is Offset from the UTC midnight
+// Begin on
+type Leap struct {
+	Begin  time.Time
+	Offset int
+}
+
+// List is a parsed leap-seconds.list
+type List struct {
+	// Updated is when the list was last updated, the #$ line
+	Updated time.Time
+	// Expires is when the list expires, the #@ line
+	Expires time.Time
+	// Leaps are the leap seconds, without the initial 1972 offset
+	Leaps []Leap
+}
+
+// Read reads and parses the named leap-seconds.list file
+func Read(name string) (*List, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses a leap-seconds.list file and validates its hash. The SHA-1
+// on the #h line covers the #$ and #@ values and the first two fields of
+// every data line, concatenated without separators.
+func Parse(r io.Reader) (*List, error) {
+	var l List
+	var hashed strings.Builder
+	var hash string
+	var updated, expires bool
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "#$"), strings.HasPrefix(line, "#@"):
+			v := strings.TrimSpace(line[2:])
+			t, err := parseNTP(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			hashed.WriteString(v)
+			if line[1] == '$' {
+				l.Updated, updated = t, true
+			} else {
+				l.Expires, expires = t, true
+			}
+		case strings.HasPrefix(line, "#h"):
+			hash = normalizeHash(line[2:])
+		case strings.HasPrefix(line, "#"), strings.TrimSpace(line) == "":
+		default:
+			if err := l.parseLeap(line, &hashed); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !updated || !expires:
+		return nil, errors.New("leap-seconds.list lacks its #$ or #@ line")
+	case hash == "":
+		return nil, ErrNoHash
+	case fmt.Sprintf("%x", sha1.Sum([]byte(hashed.String()))) != hash: //nolint:gosec
+		return nil, ErrBadHash
+	}
+	return &l, nil
+}
+
+// parseLeap appends a data line to l, writing its hashed fields to h
+func (l *List) parseLeap(line string, h *strings.Builder) error {
+	f := strings.Fields(line)
+	if len(f) < 2 {
+		return fmt.Errorf("invalid entry %q", line)
+	}
+	begin, err := parseNTP(f[0])
+	if err != nil {
+		return err
+	}
+	offset, err := strconv.Atoi(f[1])
+	if err != nil {
+		return fmt.Errorf("invalid offset %q", f[1])
+	}
+	h.WriteString(f[0])
+	h.WriteString(f[1])
+
+	if l.Leaps == nil {
+		// the first line is the offset when UTC got whole seconds
+		if offset != baseOffset {
+			return fmt.Errorf("initial offset is %d, not %d", offset, baseOffset)
+		}
+		l.Leaps = []Leap{}
+		return nil
+	}
+
+	prev := Leap{Offset: baseOffset}
+	if len(l.Leaps) > 0 {
+		prev = l.Leaps[len(l.Leaps)-1]
+	}
+	switch {
+	case begin.Unix()%86400 != 0:
+		return fmt.Errorf("leap second does not begin at midnight UTC: %v", begin)
+	case !begin.After(prev.Begin):
+		return fmt.Errorf("leap second is not after the one before it: %v", begin)
+	case offset != prev.Offset+1 && offset != prev.Offset-1:
+		return fmt.Errorf("leap second changes TAI-UTC from %d to %d", prev.Offset, offset)
+	}
+	l.Leaps = append(l.Leaps, Leap{Begin: begin, Offset: offset})
+	return nil
+}
+
+// parseNTP parses a decimal NTP second
+func parseNTP(s string) (time.Time, error) {
+	ntp, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid NTP time %q", s)
+	}
+	return time.Unix(ntp-ntpUnix, 0).UTC(), nil
+}
+
+// normalizeHash joins the words of a #h line, restoring the leading
+// zeros some publishers drop from each 32-bit word
+func normalizeHash(s string) string {
+	var b strings.Builder
+	for _, w := range strings.Fields(s) {
+		b.WriteString(strings.Repeat("0", max(0, 8-len(w))))
+		b.WriteString(strings.ToLower(w))
+	}
+	return b.String()
+}
+
+var source = template.Must(template.New("source").Funcs(template.FuncMap{"date": goDate}).Parse(`// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Code generated by leapgen from {{.Name}}; DO NOT EDIT.
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import "time"
+
+// leapseconds is the built-in leap second table, as of {{.Updated.Format "2006-01-02"}}
+var leapseconds = []*leapsecond{
+{{- range .Leaps}}
+	{ {{date .Begin}}, {{.Offset}} },
+{{- end}}
+}
+
+// leapsecondsValidUntil is when the built-in table expires, new leap
+// seconds may be announced for any later date
+var leapsecondsValidUntil = {{date .Expires}}
+`))
+
+// Source returns the Go source of the built-in table of glibtai, name is
+// the list it was read from
+func (l *List) Source(name string) ([]byte, error) {
+	var b bytes.Buffer
+	err := source.Execute(&b, struct {
+		*List
+		Name string
+	}{l, name})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// goDate returns the Go expression of a UTC midnight
+func goDate(t time.Time) string {
+	return fmt.Sprintf("time.Date(%d, time.%v, %d, 0, 0, 0, 0, time.UTC)", t.Year(), t.Month(), t.Day())
+}
@@ -0,0 +1,70 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package leapgen generates the built-in leap second table of glibtai
+// from an IERS/NIST leap-seconds.list file.
+package leapgen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sample is a shortened leap-seconds.list with its hash
+const sample = `#	comment
+#$	3960835200
+#@	3991593600
+2272060800	10	# 1 Jan 1972
+2287785600	11	# 1 Jul 1972
+2303683200	12	# 1 Jan 1973
+#h	2bb8744 5934785 7040be45 616b5dfe 6348ed4b
+`
+
+func TestParse(t *testing.T) {
+	l, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Updated.Equal(time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC)) ||
+		!l.Expires.Equal(time.Date(2026, time.June, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("updated %v, expires %v", l.Updated, l.Expires)
+	}
+	expected := []Leap{
+		{time.Date(1972, time.July, 1, 0, 0, 0, 0, time.UTC), 11},
+		{time.Date(1973, time.January, 1, 0, 0, 0, 0, time.UTC), 12},
+	}
+	if len(l.Leaps) != len(expected) {
+		t.Fatalf("got %d leap seconds", len(l.Leaps))
+	}
+	for i, ls := range l.Leaps {
+		if !ls.Begin.Equal(expected[i].Begin) || ls.Offset != expected[i].Offset {
+			t.Errorf("leap second %d is %v", i, ls)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+		err      error
+	}{
+		"bad hash":       {"#h	2bb8744", "#h	2bb8745", ErrBadHash},
+		"no hash":        {"#h", "#", ErrNoHash},
+		"changed offset": {"12	# 1 Jan 1973", "13	# 1 Jan 1973", nil},
+		"initial offset": {"10	# 1 Jan 1972", "9	# 1 Jan 1972", nil},
+		"no expiry":      {"#@", "#", nil},
+		"not midnight":   {"2303683200", "2303683201", nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(strings.Replace(sample, tc.from, tc.to, 1)))
+			if err == nil || (tc.err != nil && !errors.Is(err, tc.err)) {
+				t.Errorf("Parse returned %v", err)
+			}
+		})
+	}
+}
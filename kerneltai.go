@@ -0,0 +1,27 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import "errors"
+
+var (
+	// ErrKernelTAIUnsupported is returned when the kernel's CLOCK_TAI
+	// can not be read on this platform
+	ErrKernelTAIUnsupported = errors.New("kernel CLOCK_TAI is not supported")
+	// ErrKernelTAIUnset is returned when the kernel's TAI offset has not
+	// been set, CLOCK_TAI then runs at UTC
+	ErrKernelTAIUnset = errors.New("kernel TAI offset is not set")
+)
+
+// KernelTAINow returns the current timestamp in TAI struct read from the
+// kernel's CLOCK_TAI. When that is not possible it returns TAINow() and an
+// error wrapping ErrKernelTAIUnsupported or ErrKernelTAIUnset.
+func KernelTAINow() (TAI, error) {
+	t, err := KernelTAINNow()
+	return TAI{x: t.sec}, err
+}
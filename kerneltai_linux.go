@@ -0,0 +1,50 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+//go:build linux
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// clockTAI is the Linux clock id of CLOCK_TAI
+const clockTAI = 11
+
+// KernelTAIOffset returns the TAI-UTC offset in seconds the kernel applies
+// to CLOCK_TAI, as set through adjtimex by chrony, ntpd or ptp4l. It
+// returns ErrKernelTAIUnset when the offset is 0.
+func KernelTAIOffset() (int, error) {
+	var tx syscall.Timex
+	if _, err := syscall.Adjtimex(&tx); err != nil {
+		return 0, fmt.Errorf("%w: adjtimex: %w", ErrKernelTAIUnsupported, err)
+	}
+	if tx.Tai == 0 {
+		return 0, ErrKernelTAIUnset
+	}
+	return int(tx.Tai), nil
+}
+
+// KernelTAINNow returns the current timestamp in TAIN struct read from the
+// kernel's CLOCK_TAI. When the kernel's TAI offset is not set or CLOCK_TAI
+// can not be read it returns TAINNow(), which relies on the built-in leap
+// second table, and an error saying why.
+func KernelTAINNow() (TAIN, error) {
+	if _, err := KernelTAIOffset(); err != nil {
+		return TAINNow(), err
+	}
+
+	var ts syscall.Timespec
+	_, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockTAI, uintptr(unsafe.Pointer(&ts)), 0)
+	if errno != 0 {
+		return TAINNow(), fmt.Errorf("%w: clock_gettime: %w", ErrKernelTAIUnsupported, errno)
+	}
+	return TAIN{sec: tai64Epoch + uint64(ts.Sec), nano: uint32(ts.Nsec)}, nil
+}
@@ -0,0 +1,24 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+//go:build !linux
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+// KernelTAIOffset returns the TAI-UTC offset in seconds the kernel applies
+// to CLOCK_TAI. CLOCK_TAI is only available on Linux, so it always returns
+// ErrKernelTAIUnsupported.
+func KernelTAIOffset() (int, error) {
+	return 0, ErrKernelTAIUnsupported
+}
+
+// KernelTAINNow returns the current timestamp in TAIN struct read from the
+// kernel's CLOCK_TAI. CLOCK_TAI is only available on Linux, so it always
+// returns TAINNow() and ErrKernelTAIUnsupported.
+func KernelTAINNow() (TAIN, error) {
+	return TAINNow(), ErrKernelTAIUnsupported
+}
@@ -0,0 +1,51 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKernelTAINNow(t *testing.T) {
+	before := TAINNow()
+	k, err := KernelTAINNow()
+	after := TAINNow()
+
+	offset, offsetErr := KernelTAIOffset()
+	switch {
+	case err == nil:
+		t.Logf("kernel TAI offset is %d", offset)
+		// the kernel and the built-in table may disagree by a leap second
+		before = TAINAdd(before, -time.Second)
+		after = TAINAdd(after, time.Second)
+	case errors.Is(err, ErrKernelTAIUnset), errors.Is(err, ErrKernelTAIUnsupported):
+		t.Logf("falling back to the leap second table: %v", err)
+		if offsetErr == nil {
+			t.Errorf("KernelTAIOffset() = %d without an error while KernelTAINNow failed", offset)
+		}
+	default:
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !tainLessOrEqual(before, k) || !tainLessOrEqual(k, after) {
+		t.Errorf("KernelTAINNow() = %v, expected between %v and %v", k, before, after)
+	}
+}
+
+func TestKernelTAINow(t *testing.T) {
+	k, err := KernelTAINow()
+	n, nerr := KernelTAINNow()
+	if (err == nil) != (nerr == nil) {
+		t.Errorf("KernelTAINow error %v does not match KernelTAINNow error %v", err, nerr)
+	}
+	if d := int64(n.sec - k.x); d < 0 || d > 1 {
+		t.Errorf("KernelTAINow() = %v is not close to KernelTAINNow() = %v", k, n)
+	}
+}
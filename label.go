@@ -0,0 +1,81 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+// hexDigits is the alphabet used to render labels
+const hexDigits = "0123456789ABCDEF"
+
+// hexPairs is a lookup table holding the two hex digits of every byte
+var hexPairs = func() (tab [512]byte) {
+	for i := 0; i < 256; i++ {
+		tab[2*i] = hexDigits[i>>4]
+		tab[2*i+1] = hexDigits[i&0xF]
+	}
+	return tab
+}()
+
+// putHex64 writes v as 16 upper case hex digits into dst
+func putHex64(dst []byte, v uint64) {
+	_ = dst[15]
+	for i := 14; i >= 0; i -= 2 {
+		b := v & 0xFF
+		dst[i] = hexPairs[2*b]
+		dst[i+1] = hexPairs[2*b+1]
+		v >>= 8
+	}
+}
+
+// putHex32 writes v as 8 upper case hex digits into dst
+func putHex32(dst []byte, v uint32) {
+	_ = dst[7]
+	for i := 6; i >= 0; i -= 2 {
+		b := v & 0xFF
+		dst[i] = hexPairs[2*b]
+		dst[i+1] = hexPairs[2*b+1]
+		v >>= 8
+	}
+}
+
+// Label returns the ASCII label of a TAI timestamp in a fixed size array
+func (t TAI) Label() [TAILabelLength]byte {
+	var buf [TAILabelLength]byte
+	buf[0] = '@'
+	putHex64(buf[1:], t.x)
+	return buf
+}
+
+// AppendLabel appends the ASCII label of a TAI timestamp to b
+func (t TAI) AppendLabel(b []byte) []byte {
+	buf := t.Label()
+	return append(b, buf[:]...)
+}
+
+// AppendText implements encoding.TextAppender, it never fails
+func (t TAI) AppendText(b []byte) ([]byte, error) {
+	return t.AppendLabel(b), nil
+}
+
+// Label returns the ASCII label of a TAIN timestamp in a fixed size array
+func (t TAIN) Label() [TAINLabelLength]byte {
+	var buf [TAINLabelLength]byte
+	buf[0] = '@'
+	putHex64(buf[1:], t.sec)
+	putHex32(buf[1+2*TAILength:], t.nano)
+	return buf
+}
+
+// AppendLabel appends the ASCII label of a TAIN timestamp to b
+func (t TAIN) AppendLabel(b []byte) []byte {
+	buf := t.Label()
+	return append(b, buf[:]...)
+}
+
+// AppendText implements encoding.TextAppender, it never fails
+func (t TAIN) AppendText(b []byte) ([]byte, error) {
+	return t.AppendLabel(b), nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTAILabel(t *testing.T) {
+	tests := []struct {
+		tai      TAI
+		expected string
+	}{
+		{TAI{x: 0}, "@0000000000000000"},
+		{TAI{x: 0x40000000036DB755}, "@40000000036DB755"},
+		{TAI{x: math.MaxUint64}, "@FFFFFFFFFFFFFFFF"},
+	}
+
+	for _, tc := range tests {
+		label := tc.tai.Label()
+		if string(label[:]) != tc.expected {
+			t.Errorf("Label() = %s, expected %s", label[:], tc.expected)
+		}
+		if s := tc.tai.String(); s != tc.expected {
+			t.Errorf("String() = %s, expected %s", s, tc.expected)
+		}
+		if s := fmt.Sprintf("@%02X", TAIPack(tc.tai)); s != tc.expected {
+			t.Errorf("Label %s does not match packed form %s", tc.expected, s)
+		}
+		b, err := tc.tai.AppendText([]byte("x"))
+		if err != nil || string(b) != "x"+tc.expected {
+			t.Errorf("AppendText() = %q, %v", b, err)
+		}
+	}
+}
+
+func TestTAINLabel(t *testing.T) {
+	tests := []struct {
+		tain     TAIN
+		expected string
+	}{
+		{TAIN{}, "@000000000000000000000000"},
+		{TAIN{sec: 0x40000000036DB755, nano: 0xAB4CDE12}, "@40000000036DB755AB4CDE12"},
+		{TAIN{sec: math.MaxUint64, nano: 999999999}, "@FFFFFFFFFFFFFFFF3B9AC9FF"},
+	}
+
+	for _, tc := range tests {
+		label := tc.tain.Label()
+		if string(label[:]) != tc.expected {
+			t.Errorf("Label() = %s, expected %s", label[:], tc.expected)
+		}
+		if s := tc.tain.String(); s != tc.expected {
+			t.Errorf("String() = %s, expected %s", s, tc.expected)
+		}
+		b := tc.tain.AppendLabel(nil)
+		back, err := TAINfromString(string(b))
+		if err != nil || back != tc.tain {
+			t.Errorf("AppendLabel() = %s does not round trip: %v", b, err)
+		}
+	}
+}
+
+func TestAppendLabelAllocations(t *testing.T) {
+	tain := TAINNow()
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = tain.AppendLabel(buf[:0])
+		_ = tain.Label()
+	})
+	if allocs != 0 {
+		t.Errorf("AppendLabel allocated %v times", allocs)
+	}
+}
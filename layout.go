@@ -0,0 +1,149 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appendCaltime appends ct formatted according to layout by the time
+// package. A leap second is formatted as second 59 and the seconds
+// elements, the only ones that differ from the second before, are
+// rewritten to 60 afterwards.
+func appendCaltime(b []byte, ct caltime, layout string, scale Scale) []byte {
+	leap := ct.second == 60
+	if leap {
+		ct.second = 59
+	}
+	tm := time.Date(ct.year, ct.month, ct.day, ct.hour, ct.minute, ct.second, ct.nano,
+		time.FixedZone(scale.String(), 0))
+
+	start := len(b)
+	b = tm.AppendFormat(b, layout)
+	if !leap {
+		return b
+	}
+
+	prev := tm.Add(-time.Second).AppendFormat(nil, layout)
+	for i, c := range prev {
+		if b[start+i] != c {
+			b[start+i-1], b[start+i] = '6', '0'
+		}
+	}
+	return b
+}
+
+// TAIAppendFormat appends the calendar time of a TAI timestamp in scale,
+// formatted according to a time package style layout, to b. Leap seconds
+// show as second 60 in ScaleUTC and zone elements render a zero offset
+// with the name of the scale.
+func TAIAppendFormat(b []byte, t TAI, layout string, scale Scale) []byte {
+	return appendCaltime(b, caltimeFromLabel(t.x, 0, scale), layout, scale)
+}
+
+// TAIFormat returns the calendar time of a TAI timestamp in scale,
+// formatted according to a time package style layout
+func TAIFormat(t TAI, layout string, scale Scale) string {
+	var buf [64]byte
+	return string(TAIAppendFormat(buf[:0], t, layout, scale))
+}
+
+// TAINAppendFormat appends the calendar time of a TAIN timestamp in scale,
+// formatted according to a time package style layout, to b. Leap seconds
+// show as second 60 in ScaleUTC and zone elements render a zero offset
+// with the name of the scale.
+func TAINAppendFormat(b []byte, t TAIN, layout string, scale Scale) []byte {
+	return appendCaltime(b, caltimeFromLabel(t.sec, t.nano, scale), layout, scale)
+}
+
+// TAINFormat returns the calendar time of a TAIN timestamp in scale,
+// formatted according to a time package style layout
+func TAINFormat(t TAIN, layout string, scale Scale) string {
+	var buf [64]byte
+	return string(TAINAppendFormat(buf[:0], t, layout, scale))
+}
+
+// TAIParse parses a calendar time in scale formatted according to a time
+// package style layout into a TAI timestamp, dropping fractional seconds.
+// Second 60 is only accepted in ScaleUTC at an inserted leap second and
+// zone names must match the scale.
+func TAIParse(layout, value string, scale Scale) (TAI, error) {
+	sec, _, err := parseLabel(layout, value, scale)
+	return TAI{x: sec}, err
+}
+
+// TAINParse parses a calendar time in scale formatted according to a time
+// package style layout into a TAIN timestamp. Second 60 is only accepted
+// in ScaleUTC at an inserted leap second and zone names must match the
+// scale.
+func TAINParse(layout, value string, scale Scale) (TAIN, error) {
+	sec, nano, err := parseLabel(layout, value, scale)
+	return TAIN{sec: sec, nano: nano}, err
+}
+
+// parseLabel parses value according to layout with the time package and
+// returns its label
+func parseLabel(layout, value string, scale Scale) (uint64, uint32, error) {
+	tm, leap, err := parseLeap(layout, value, scale)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	name, offset := tm.Zone()
+	if strings.Contains(layout, "MST") && !zoneNamesScale(name, scale) {
+		return 0, 0, &time.ParseError{Layout: layout, Value: value, LayoutElem: "MST", ValueElem: name,
+			Message: ": time zone " + strconv.Quote(name) + " is not " + scale.String()}
+	}
+
+	ct := caltime{
+		year:   tm.Year(),
+		month:  tm.Month(),
+		day:    tm.Day(),
+		hour:   tm.Hour(),
+		minute: tm.Minute(),
+		second: tm.Second(),
+	}
+	if leap {
+		ct.second = 60
+	}
+	sec, ok := ct.label(scale, offset)
+	if !ok {
+		return 0, 0, &time.ParseError{Layout: layout, Value: value, Message: ": second out of range"}
+	}
+	return sec, uint32(tm.Nanosecond()), nil
+}
+
+// parseLeap parses value with the time package in a zero offset zone
+// named after scale. The time package rejects second 60, so when it
+// complains about one the 60 is read as 59 and leap is set.
+func parseLeap(layout, value string, scale Scale) (tm time.Time, leap bool, err error) {
+	loc := time.FixedZone(scale.String(), 0)
+	tm, err = time.ParseInLocation(layout, value, loc)
+	if pe, ok := err.(*time.ParseError); ok && pe.Message == ": second out of range" {
+		i := len(value) - len(pe.ValueElem) - 2
+		if i >= 0 && value[i:i+2] == "60" {
+			leap = true
+			tm, err = time.ParseInLocation(layout, value[:i]+"59"+value[i+2:], loc)
+		}
+	}
+	if pe, ok := err.(*time.ParseError); ok {
+		// report the value as it was given
+		pe.Value = value
+	}
+	return tm, leap, err
+}
+
+// zoneNamesScale reports whether a parsed zone name names scale
+func zoneNamesScale(name string, scale Scale) bool {
+	if scale == ScaleTAI {
+		return name == "TAI"
+	}
+	return name == "UTC" || name == "GMT"
+}
@@ -0,0 +1,115 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTAINFormatLayouts(t *testing.T) {
+	leap := TAIN{sec: labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()) - 1,
+		nano: 123456789}
+	tests := []struct {
+		layout   string
+		scale    Scale
+		expected string
+	}{
+		{time.RFC3339Nano, ScaleUTC, "2016-12-31T23:59:60.123456789Z"},
+		{time.RFC3339, ScaleTAI, "2017-01-01T00:00:36Z"},
+		{"2006-01-02 15:04:05.000 MST", ScaleTAI, "2017-01-01 00:00:36.123 TAI"},
+		{"2006-01-02 15:04:05.000000 -07:00", ScaleUTC, "2016-12-31 23:59:60.123456 +00:00"},
+		{"Mon Jan _2 3:04:05PM 2006", ScaleUTC, "Sat Dec 31 11:59:60PM 2016"},
+		{"Monday January 2 06 __2 002", ScaleTAI, "Sunday January 1 17   1 001"},
+		{"_2006 -0700 Z07", ScaleUTC, "_2016 +0000 Z"},
+		{"15:04:05,999", ScaleUTC, "23:59:60,123"},
+	}
+
+	for _, tc := range tests {
+		if s := TAINFormat(leap, tc.layout, tc.scale); s != tc.expected {
+			t.Errorf("TAINFormat(%q, %v) = %q, expected %q", tc.layout, tc.scale, s, tc.expected)
+		}
+	}
+}
+
+func TestTAIFormatMatchesTime(t *testing.T) {
+	tm := time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC)
+	layouts := []string{time.ANSIC, time.RFC822Z, time.RFC1123, time.Kitchen, time.DateTime, time.StampMilli}
+	for _, layout := range layouts {
+		if s, expected := TAIFormat(TAIfromTime(tm), layout, ScaleUTC), tm.Format(layout); s != expected {
+			t.Errorf("TAIFormat(%q) = %q, expected %q", layout, s, expected)
+		}
+	}
+}
+
+func TestTAINParse(t *testing.T) {
+	midnight := labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+	tests := []struct {
+		layout   string
+		value    string
+		scale    Scale
+		expected TAIN
+	}{
+		{time.RFC3339Nano, "2016-12-31T23:59:60.5Z", ScaleUTC, TAIN{sec: midnight - 1, nano: 500000000}},
+		{time.RFC3339, "2017-01-01T01:59:60+02:00", ScaleUTC, TAIN{sec: midnight - 1}},
+		{time.RFC3339, "2017-01-01T00:00:00Z", ScaleUTC, TAIN{sec: midnight}},
+		{"2006-01-02 15:04:05 MST", "2017-01-01 00:00:37 TAI", ScaleTAI, TAIN{sec: midnight}},
+		{"2006-01-02 15:04:05", "2017-01-01 00:00:00.25", ScaleUTC, TAIN{sec: midnight, nano: 250000000}},
+		{"Jan _2 2006 3:04:05PM", "Jan  1 2017 12:00:00AM", ScaleUTC, TAIN{sec: midnight}},
+		{"2006 002 15:04:05", "2017 001 00:00:00", ScaleUTC, TAIN{sec: midnight}},
+		{"January 2, 2006 15:04:05.000", "january 1, 2017 00:00:00.001", ScaleUTC, TAIN{sec: midnight, nano: 1000000}},
+	}
+
+	for _, tc := range tests {
+		result, err := TAINParse(tc.layout, tc.value, tc.scale)
+		if err != nil {
+			t.Errorf("TAINParse(%q) failed: %v", tc.value, err)
+		} else if result != tc.expected {
+			t.Errorf("TAINParse(%q) = %v, expected %v", tc.value, result, tc.expected)
+		}
+	}
+}
+
+func TestTAINParseErrors(t *testing.T) {
+	tests := []struct {
+		layout string
+		value  string
+		scale  Scale
+	}{
+		{time.RFC3339, "2016-12-30T23:59:60Z", ScaleUTC},
+		{time.RFC3339, "2016-12-31T23:59:60Z", ScaleTAI},
+		{time.RFC3339, "2016-12-31T23:59:61Z", ScaleUTC},
+		{time.RFC3339, "2016-12-31T24:00:00Z", ScaleUTC},
+		{time.RFC3339, "2021-02-29T00:00:00Z", ScaleUTC},
+		{time.RFC3339, "2021-02-28T00:00:00Z trailing", ScaleUTC},
+		{"2006-01-02 MST", "2021-02-28 TAI", ScaleUTC},
+		{"2006-01-02 MST", "2021-02-28 UTC", ScaleTAI},
+		{"2006 002", "2021 366", ScaleUTC},
+		{"2006-01-02 .000", "2021-02-28 .12", ScaleUTC},
+	}
+
+	for _, tc := range tests {
+		if result, err := TAINParse(tc.layout, tc.value, tc.scale); err == nil {
+			t.Errorf("TAINParse(%q, %v) = %v, expected an error", tc.value, tc.scale, result)
+		}
+	}
+}
+
+func TestTAIFormatParseRoundTrip(t *testing.T) {
+	start := TAIfromTime(time.Date(2016, time.December, 31, 23, 59, 50, 0, time.UTC))
+	for i := 0; i < 20; i++ {
+		tai := TAIAdd(start, time.Duration(i)*time.Second)
+		for _, scale := range []Scale{ScaleUTC, ScaleTAI} {
+			s := TAIFormat(tai, time.RFC3339, scale)
+			back, err := TAIParse(time.RFC3339, s, scale)
+			if err != nil || back != tai {
+				t.Errorf("%v in %v formatted as %s parsed back as %v, %v", tai, scale, s, back, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"iter"
+	"sort"
+	"time"
+)
+
+// entry returns t.leaps[i] as a LeapSecond
+func (t *LeapTable) entry(i int) LeapSecond {
+	ls := t.leaps[i]
+	begin := ls.begin.Unix()
+	return LeapSecond{
+		Begin:  ls.begin,
+		Offset: ls.offset,
+		TAI:    TAI{x: tai64Epoch + uint64(begin+int64(ls.offset))},
+	}
+}
+
+// Leaps returns an iterator over the leap seconds of t in time order
+func (t *LeapTable) Leaps() iter.Seq[LeapSecond] {
+	return func(yield func(LeapSecond) bool) {
+		for i := range t.leaps {
+			if !yield(t.entry(i)) {
+				return
+			}
+		}
+	}
+}
+
+// search returns the number of leap seconds of t taking effect at or
+// before x
+func (t *LeapTable) search(x TAI) int {
+	return sort.Search(len(t.leaps), func(i int) bool {
+		return t.entry(i).TAI.x > x.x
+	})
+}
+
+// OffsetAt returns the TAI-UTC offset in seconds at x. An inserted leap
+// second still has the offset before it.
+func (t *LeapTable) OffsetAt(x TAI) int {
+	i := t.search(x)
+	if i == 0 {
+		return baseOffset
+	}
+	return t.leaps[i-1].offset
+}
+
+// NextLeap returns the first leap second of t taking effect after x
+func (t *LeapTable) NextLeap(x TAI) (LeapSecond, bool) {
+	i := t.search(x)
+	if i == len(t.leaps) {
+		return LeapSecond{}, false
+	}
+	return t.entry(i), true
+}
+
+// PrevLeap returns the last leap second of t taking effect at or before x
+func (t *LeapTable) PrevLeap(x TAI) (LeapSecond, bool) {
+	i := t.search(x)
+	if i == 0 {
+		return LeapSecond{}, false
+	}
+	return t.entry(i - 1), true
+}
+
+// IsLeapSecond reports whether x is an inserted leap second, 23:59:60 UTC
+func (t *LeapTable) IsLeapSecond(x TAI) bool {
+	_, leap := t.unixFromLabel(x.x)
+	return leap
+}
+
+// UTCDayLength returns the length of the UTC day with the calendar date of
+// date in its location, 24 hours plus or minus the leap second at its end
+func (t *LeapTable) UTCDayLength(date time.Time) time.Duration {
+	y, m, d := date.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix()
+	return time.Duration(t.labelFromUnix(start+86400)-t.labelFromUnix(start)) * time.Second
+}
+
+// Leaps returns an iterator over the leap seconds of the current table
+func Leaps() iter.Seq[LeapSecond] {
+	return CurrentLeapTable().Leaps()
+}
+
+// OffsetAt returns the TAI-UTC offset in seconds at x using the current
+// table. An inserted leap second still has the offset before it.
+func OffsetAt(x TAI) int {
+	return CurrentLeapTable().OffsetAt(x)
+}
+
+// NextLeap returns the first leap second of the current table taking
+// effect after x
+func NextLeap(x TAI) (LeapSecond, bool) {
+	return CurrentLeapTable().NextLeap(x)
+}
+
+// PrevLeap returns the last leap second of the current table taking
+// effect at or before x
+func PrevLeap(x TAI) (LeapSecond, bool) {
+	return CurrentLeapTable().PrevLeap(x)
+}
+
+// IsLeapSecond reports whether x is an inserted leap second using the
+// current table
+func IsLeapSecond(x TAI) bool {
+	return CurrentLeapTable().IsLeapSecond(x)
+}
+
+// UTCDayLength returns the length of the UTC day with the calendar date of
+// date using the current table, 24 hours plus or minus the leap second at
+// its end
+func UTCDayLength(date time.Time) time.Duration {
+	return CurrentLeapTable().UTCDayLength(date)
+}
@@ -0,0 +1,118 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaps(t *testing.T) {
+	var leaps []LeapSecond
+	for ls := range Leaps() {
+		leaps = append(leaps, ls)
+	}
+	if len(leaps) != 27 {
+		t.Fatalf("Leaps() returned %d leap seconds, expected 27", len(leaps))
+	}
+
+	first := leaps[0]
+	if !first.Begin.Equal(time.Date(1972, time.July, 1, 0, 0, 0, 0, time.UTC)) || first.Offset != 11 || first.TAI != TAIfromTime(first.Begin) {
+		t.Errorf("first leap second is %+v", first)
+	}
+	last := leaps[26]
+	if last.Offset != 37 || last.TAI.String() != "@40000000586846A5" {
+		t.Errorf("last leap second is %+v", last)
+	}
+
+	table, err := NewLeapTable(leaps)
+	if err != nil || table.String() != BuiltinLeapTable().WithValidUntil(time.Time{}).String() {
+		t.Errorf("leap seconds do not rebuild the table: %v", err)
+	}
+
+	n := 0
+	for range Leaps() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+}
+
+func TestOffsetAt(t *testing.T) {
+	midnight := TAIfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		x      TAI
+		offset int
+	}{
+		{TAIfromTime(time.Date(1960, time.January, 1, 0, 0, 0, 0, time.UTC)), 10},
+		{TAIAdd(midnight, -2*time.Second), 36},
+		{TAIAdd(midnight, -time.Second), 36},
+		{midnight, 37},
+		{TAIfromTime(time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)), 37},
+	}
+	for _, tt := range tests {
+		if o := OffsetAt(tt.x); o != tt.offset {
+			t.Errorf("OffsetAt(%v) = %d, expected %d", tt.x, o, tt.offset)
+		}
+	}
+}
+
+func TestNextPrevLeap(t *testing.T) {
+	midnight := TAIfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	next, ok := NextLeap(TAIAdd(midnight, -time.Second))
+	if !ok || next.TAI != midnight || next.Offset != 37 {
+		t.Errorf("NextLeap before 2017 is %+v, %v", next, ok)
+	}
+	if _, ok := NextLeap(midnight); ok {
+		t.Error("NextLeap after the last leap second succeeded")
+	}
+
+	prev, ok := PrevLeap(midnight)
+	if !ok || prev.TAI != midnight {
+		t.Errorf("PrevLeap at 2017 is %+v, %v", prev, ok)
+	}
+	prev, ok = PrevLeap(TAIAdd(midnight, -time.Second))
+	if !ok || prev.Offset != 36 {
+		t.Errorf("PrevLeap before 2017 is %+v, %v", prev, ok)
+	}
+	if _, ok := PrevLeap(TAIfromTime(time.Date(1972, time.June, 30, 0, 0, 0, 0, time.UTC))); ok {
+		t.Error("PrevLeap before the first leap second succeeded")
+	}
+	next, ok = NextLeap(TAIfromTime(time.Date(1960, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	if !ok || next.Offset != 11 {
+		t.Errorf("NextLeap in 1960 is %+v, %v", next, ok)
+	}
+}
+
+func TestIsLeapSecond(t *testing.T) {
+	midnight := TAIfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	for d, leap := range map[time.Duration]bool{-2 * time.Second: false, -time.Second: true, 0: false} {
+		if x := TAIAdd(midnight, d); IsLeapSecond(x) != leap {
+			t.Errorf("IsLeapSecond(%v) = %v", x, !leap)
+		}
+	}
+}
+
+func TestUTCDayLength(t *testing.T) {
+	tests := []struct {
+		date   time.Time
+		length time.Duration
+	}{
+		{time.Date(2016, time.December, 31, 15, 0, 0, 0, time.UTC), 86401 * time.Second},
+		{time.Date(2016, time.December, 30, 0, 0, 0, 0, time.UTC), 86400 * time.Second},
+		{time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), 86400 * time.Second},
+		{time.Date(1972, time.June, 30, 23, 59, 59, 0, time.FixedZone("CET", 3600)), 86401 * time.Second},
+	}
+	for _, tt := range tests {
+		if l := UTCDayLength(tt.date); l != tt.length {
+			t.Errorf("UTCDayLength(%v) = %v, expected %v", tt.date, l, tt.length)
+		}
+	}
+}
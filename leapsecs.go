@@ -7,50 +7,226 @@
 // http://cr.yp.to/libtai/ for more information.
 package glibtai
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
 
+// leapsecond is an entry of a leap table: TAI-UTC is offset from the UTC
+// midnight begin on
 type leapsecond struct {
 	begin  time.Time
 	offset int
 }
 
-var leapseconds = []*leapsecond{
-	{time.Date(1972, time.July, 1, 0, 0, 0, 0, time.UTC), 11},
-	{time.Date(1973, time.January, 1, 0, 0, 0, 0, time.UTC), 12},
-	{time.Date(1974, time.January, 1, 0, 0, 0, 0, time.UTC), 13},
-	{time.Date(1975, time.January, 1, 0, 0, 0, 0, time.UTC), 14},
-	{time.Date(1976, time.January, 1, 0, 0, 0, 0, time.UTC), 15},
-	{time.Date(1977, time.January, 1, 0, 0, 0, 0, time.UTC), 16},
-	{time.Date(1978, time.January, 1, 0, 0, 0, 0, time.UTC), 17},
-	{time.Date(1979, time.January, 1, 0, 0, 0, 0, time.UTC), 18},
-	{time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC), 19},
-	{time.Date(1981, time.July, 1, 0, 0, 0, 0, time.UTC), 20},
-	{time.Date(1982, time.July, 1, 0, 0, 0, 0, time.UTC), 21},
-	{time.Date(1983, time.July, 1, 0, 0, 0, 0, time.UTC), 22},
-	{time.Date(1985, time.July, 1, 0, 0, 0, 0, time.UTC), 23},
-	{time.Date(1988, time.January, 1, 0, 0, 0, 0, time.UTC), 24},
-	{time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC), 25},
-	{time.Date(1991, time.January, 1, 0, 0, 0, 0, time.UTC), 26},
-	{time.Date(1992, time.July, 1, 0, 0, 0, 0, time.UTC), 27},
-	{time.Date(1993, time.July, 1, 0, 0, 0, 0, time.UTC), 28},
-	{time.Date(1994, time.July, 1, 0, 0, 0, 0, time.UTC), 29},
-	{time.Date(1996, time.January, 1, 0, 0, 0, 0, time.UTC), 30},
-	{time.Date(1997, time.July, 1, 0, 0, 0, 0, time.UTC), 31},
-	{time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), 32},
-	{time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC), 33},
-	{time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC), 34},
-	{time.Date(2012, time.July, 1, 0, 0, 0, 0, time.UTC), 35},
-	{time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC), 36},
-	{time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), 37},
-}
-
-func lsoffset(t time.Time) uint64 {
-	for i := len(leapseconds) - 1; i >= 0; i-- {
-		ls := leapseconds[i]
-		if t.Unix() >= ls.begin.Unix() {
-			return uint64(ls.offset)
+// leapseconds and leapsecondsValidUntil are generated from the checked-in
+// leap-seconds.list
+//go:generate go run ./internal/leapgen/cmd/leapgen -in internal/leapgen/leap-seconds.list -out leapsecs_table.go
+
+// tai64Epoch is the TAI64 label of 1970-01-01 00:00:00 TAI
+const tai64Epoch = uint64(1) << 62
+
+// baseOffset is the TAI-UTC offset libtai assumes before the first
+// leap second, it is already accounted for in TAICONST
+const baseOffset = 10
+
+// LeapSecond is an entry of a LeapTable: the UTC midnight following a leap
+// second and the TAI-UTC offset in seconds from then on
+type LeapSecond struct {
+	Begin  time.Time
+	Offset int
+	// TAI is the label of Begin, NewLeapTable ignores it
+	TAI TAI
+}
+
+// LeapTable is a table of leap seconds. Before its first entry TAI-UTC is
+// 10 seconds, the offset libtai assumes. Tables are immutable.
+type LeapTable struct {
+	leaps      []*leapsecond
+	validUntil time.Time
+	// expires is the Unix second of validUntil, math.MaxInt64 if the
+	// table does not expire
+	expires int64
+	// stale is set once the stale hook fired for the table
+	stale atomic.Bool
+}
+
+// builtinLeapTable is the table compiled into glibtai
+var builtinLeapTable = newLeapTable(leapseconds, leapsecondsValidUntil)
+
+// currentLeapTable is the table conversions use, nil for the built-in one
+var currentLeapTable atomic.Pointer[LeapTable]
+
+// NewLeapTable returns a LeapTable of leaps, which must begin at UTC
+// midnights in increasing order with the offset changing by one second
+// each time. An offset growing by one is an inserted leap second, 23:59:60,
+// and an offset shrinking by one a removed 23:59:59.
+func NewLeapTable(leaps []LeapSecond) (*LeapTable, error) {
+	var table []*leapsecond
+	prev := leapsecond{offset: baseOffset}
+	for i, l := range leaps {
+		ls := &leapsecond{begin: l.Begin.UTC(), offset: l.Offset}
+		if err := checkLeap(prev, *ls, i); err != nil {
+			return nil, err
 		}
+		table = append(table, ls)
+		prev = *ls
+	}
+	return newLeapTable(table, time.Time{}), nil
+}
+
+// newLeapTable returns a LeapTable of checked leaps, valid until
+// validUntil unless it is zero
+func newLeapTable(leaps []*leapsecond, validUntil time.Time) *LeapTable {
+	t := &LeapTable{leaps: leaps, validUntil: validUntil, expires: math.MaxInt64}
+	if !validUntil.IsZero() {
+		t.expires = validUntil.Unix()
 	}
+	return t
+}
+
+// WithValidUntil returns a copy of t that expires at validUntil, a zero
+// time means the table does not expire
+func (t *LeapTable) WithValidUntil(validUntil time.Time) *LeapTable {
+	return newLeapTable(t.leaps, validUntil)
+}
+
+// ValidUntil returns when t expires, or the zero time if it does not. Leap
+// seconds after that may be missing from t.
+func (t *LeapTable) ValidUntil() time.Time {
+	return t.validUntil
+}
+
+// checkLeap validates entry i of a table following prev
+func checkLeap(prev, ls leapsecond, i int) error {
+	switch {
+	case ls.begin.Unix()%86400 != 0 || ls.begin.Nanosecond() != 0:
+		return fmt.Errorf("leap second %d does not begin at midnight UTC: %v", i, ls.begin)
+	case i > 0 && !ls.begin.After(prev.begin):
+		return fmt.Errorf("leap second %d is not after the one before it: %v", i, ls.begin)
+	case ls.offset != prev.offset+1 && ls.offset != prev.offset-1:
+		return fmt.Errorf("leap second %d changes TAI-UTC from %d to %d", i, prev.offset, ls.offset)
+	}
+	return nil
+}
+
+// BuiltinLeapTable returns the leap second table compiled into glibtai
+func BuiltinLeapTable() *LeapTable {
+	return builtinLeapTable
+}
+
+// CurrentLeapTable returns the leap second table used for conversions
+func CurrentLeapTable() *LeapTable {
+	if t := currentLeapTable.Load(); t != nil {
+		return t
+	}
+	return builtinLeapTable
+}
+
+// SetLeapTable makes t the leap second table used for conversions, nil
+// restores the built-in table. It is safe to call while other goroutines
+// convert timestamps.
+func SetLeapTable(t *LeapTable) {
+	currentLeapTable.Store(t)
+}
+
+// Len returns the number of leap seconds in t
+func (t *LeapTable) Len() int {
+	return len(t.leaps)
+}
 
-	return 0
+// Offset returns the TAI-UTC offset in seconds in effect at tm
+func (t *LeapTable) Offset(tm time.Time) int {
+	return int(t.utcOffset(tm.Unix()))
+}
+
+// utcOffset returns the TAI-UTC offset in effect at the given Unix second
+func utcOffset(unix int64) int64 {
+	t := CurrentLeapTable()
+	t.checkExpiry(unix)
+	return t.utcOffset(unix)
+}
+
+// labelFromUnix returns the TAI64 label of the given Unix second
+func labelFromUnix(unix int64) uint64 {
+	t := CurrentLeapTable()
+	t.checkExpiry(unix)
+	return t.labelFromUnix(unix)
+}
+
+// unixSkipped reports whether the given Unix second is a 23:59:59 removed
+// by a negative leap second, which UTC never reads
+func unixSkipped(unix int64) bool {
+	return CurrentLeapTable().unixSkipped(unix)
+}
+
+// unixFromLabel returns the Unix second of a TAI64 label. When the label
+// is an inserted leap second it returns the Unix second of 23:59:59 and
+// reports leap as true.
+func unixFromLabel(x uint64) (unix int64, leap bool) {
+	t := CurrentLeapTable()
+	unix, leap = t.unixFromLabel(x)
+	t.checkExpiry(unix)
+	return unix, leap
+}
+
+// utcOffset returns the TAI-UTC offset in effect at the given Unix second
+func (t *LeapTable) utcOffset(unix int64) int64 {
+	for i := len(t.leaps) - 1; i >= 0; i-- {
+		ls := t.leaps[i]
+		if unix >= ls.begin.Unix() {
+			return int64(ls.offset)
+		}
+	}
+
+	return baseOffset
+}
+
+// labelFromUnix returns the TAI64 label of the given Unix second
+func (t *LeapTable) labelFromUnix(unix int64) uint64 {
+	return tai64Epoch + uint64(unix+t.utcOffset(unix))
+}
+
+// prevOffset returns the TAI-UTC offset in effect before t.leaps[i]
+func (t *LeapTable) prevOffset(i int) int64 {
+	if i == 0 {
+		return baseOffset
+	}
+	return int64(t.leaps[i-1].offset)
+}
+
+// unixFromLabel returns the Unix second of a TAI64 label. When the label
+// is an inserted leap second it returns the Unix second of 23:59:59 and
+// reports leap as true.
+func (t *LeapTable) unixFromLabel(x uint64) (unix int64, leap bool) {
+	s := int64(x - tai64Epoch)
+	for i := len(t.leaps) - 1; i >= 0; i-- {
+		ls := t.leaps[i]
+		begin := ls.begin.Unix()
+		if s >= begin+int64(ls.offset) {
+			return s - int64(ls.offset), false
+		}
+		if s >= begin+t.prevOffset(i) {
+			return begin - 1, true
+		}
+	}
+
+	return s - baseOffset, false
+}
+
+// unixSkipped reports whether the given Unix second is a 23:59:59 removed
+// by a negative leap second of t
+func (t *LeapTable) unixSkipped(unix int64) bool {
+	for i := len(t.leaps) - 1; i >= 0; i-- {
+		begin := t.leaps[i].begin.Unix()
+		switch {
+		case unix >= begin:
+			return false
+		case unix == begin-1:
+			return int64(t.leaps[i].offset) < t.prevOffset(i)
+		}
+	}
+	return false
 }
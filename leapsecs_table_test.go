@@ -0,0 +1,37 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/karasz/glibtai/internal/leapgen"
+)
+
+func TestLeapTableGenerated(t *testing.T) {
+	l, err := leapgen.Read("internal/leapgen/leap-seconds.list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := l.Source("leap-seconds.list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("leapsecs_table.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Error("leapsecs_table.go is out of date, run go generate")
+	}
+	if !BuiltinLeapTable().ValidUntil().Equal(l.Expires) {
+		t.Errorf("built-in table expires %v, the list %v", BuiltinLeapTable().ValidUntil(), l.Expires)
+	}
+}
@@ -12,16 +12,188 @@ import (
 	"time"
 )
 
-func TestLsoffset(t *testing.T) {
-	mp := make(map[int]uint64)
-	mp[1933] = 0
+func TestUTCOffset(t *testing.T) {
+	mp := make(map[int]int64)
+	mp[1933] = baseOffset
 	mp[1982] = 21
-	mp[2933] = lsoffset(time.Now())
+	mp[2933] = utcOffset(time.Now().Unix())
 	for i, q := range mp {
 		x := time.Date(i, time.August, 1, 0, 0, 0, 0, time.UTC)
-		z := lsoffset(x)
+		z := utcOffset(x.Unix())
 		if z != q {
 			t.Errorf("Offset for %v should be %d, not %d", x, q, z)
 		}
 	}
 }
+
+func TestLabelFromUnix(t *testing.T) {
+	tests := []struct {
+		tm       time.Time
+		expected uint64
+	}{
+		{time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), TAICONST},
+		{time.Date(1972, time.June, 30, 23, 59, 59, 0, time.UTC), TAICONST + 78796799},
+		{time.Date(1972, time.July, 1, 0, 0, 0, 0, time.UTC), TAICONST + 78796800 + 1},
+		{time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), tai64Epoch + 1483228800 + 37},
+	}
+
+	for _, tc := range tests {
+		if x := labelFromUnix(tc.tm.Unix()); x != tc.expected {
+			t.Errorf("labelFromUnix(%v) = %d, expected %d", tc.tm, x, tc.expected)
+		}
+	}
+}
+
+func TestUnixFromLabel(t *testing.T) {
+	midnight := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	x := labelFromUnix(midnight)
+	tests := []struct {
+		label uint64
+		unix  int64
+		leap  bool
+	}{
+		{x - 2, midnight - 1, false},
+		{x - 1, midnight - 1, true},
+		{x, midnight, false},
+		{x + 1, midnight + 1, false},
+		{TAICONST - 86400, -86400, false},
+	}
+
+	for _, tc := range tests {
+		unix, leap := unixFromLabel(tc.label)
+		if unix != tc.unix || leap != tc.leap {
+			t.Errorf("unixFromLabel(%d) = %d, %v, expected %d, %v", tc.label, unix, leap, tc.unix, tc.leap)
+		}
+	}
+}
+
+func TestNewLeapTableErrors(t *testing.T) {
+	midnight := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, leaps := range [][]LeapSecond{
+		{{Begin: midnight.Add(time.Second), Offset: 11}},
+		{{Begin: midnight, Offset: 12}},
+		{{Begin: midnight, Offset: 11}, {Begin: midnight, Offset: 12}},
+		{{Begin: midnight, Offset: 11}, {Begin: midnight.AddDate(0, 0, 1), Offset: 11}},
+	} {
+		if _, err := NewLeapTable(leaps); err == nil {
+			t.Errorf("NewLeapTable(%v) succeeded", leaps)
+		}
+	}
+}
+
+func TestSetLeapTable(t *testing.T) {
+	defer SetLeapTable(nil)
+
+	var leaps []LeapSecond
+	for _, ls := range leapseconds {
+		leaps = append(leaps, LeapSecond{Begin: ls.begin, Offset: ls.offset})
+	}
+	future := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	table, err := NewLeapTable(append(leaps, LeapSecond{Begin: future, Offset: 38}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := TAINfromTime(future)
+	SetLeapTable(table)
+	if CurrentLeapTable() != table {
+		t.Fatal("CurrentLeapTable did not return the installed table")
+	}
+	after := TAINfromTime(future)
+	if d, _ := TAINSub(after, before); d != time.Second {
+		t.Errorf("installed leap second moved %v by %v", future, d)
+	}
+	if utcOffset(future.Unix()) != 38 || !TAINTime(after).Equal(future) {
+		t.Errorf("installed table is not used for conversions")
+	}
+
+	SetLeapTable(nil)
+	if CurrentLeapTable() != BuiltinLeapTable() {
+		t.Error("SetLeapTable(nil) did not restore the built-in table")
+	}
+}
+
+// negativeLeapMidnight follows the removed second of the synthetic table
+var negativeLeapMidnight = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// withNegativeLeap installs the built-in table plus a negative leap second
+// removing 2029-12-31 23:59:59 until the test ends
+func withNegativeLeap(t *testing.T) *LeapTable {
+	t.Helper()
+	var leaps []LeapSecond
+	for ls := range BuiltinLeapTable().Leaps() {
+		leaps = append(leaps, ls)
+	}
+	table, err := NewLeapTable(append(leaps, LeapSecond{Begin: negativeLeapMidnight, Offset: 36}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetLeapTable(table)
+	t.Cleanup(func() { SetLeapTable(nil) })
+	return table
+}
+
+func TestNegativeLeapSecond(t *testing.T) {
+	withNegativeLeap(t)
+	midnight := negativeLeapMidnight.Unix()
+
+	before := labelFromUnix(midnight - 2)
+	if x := labelFromUnix(midnight); x != before+1 {
+		t.Errorf("23:59:58 is %d and midnight %d, expected consecutive labels", before, x)
+	}
+	for label, unix := range map[uint64]int64{before: midnight - 2, before + 1: midnight, before + 2: midnight + 1} {
+		if u, leap := unixFromLabel(label); u != unix || leap {
+			t.Errorf("unixFromLabel(%d) = %d, %v, expected %d", label, u, leap, unix)
+		}
+	}
+	if !unixSkipped(midnight-1) || unixSkipped(midnight-2) || unixSkipped(midnight) {
+		t.Error("only 23:59:59 is removed")
+	}
+
+	if l := UTCDayLength(negativeLeapMidnight.AddDate(0, 0, -1)); l != 86399*time.Second {
+		t.Errorf("UTCDayLength = %v", l)
+	}
+	x := TAI{x: before + 1}
+	if OffsetAt(x) != 36 || OffsetAt(TAI{x: before}) != 37 || IsLeapSecond(TAI{x: before}) {
+		t.Errorf("offsets around the removed second are %d and %d", OffsetAt(TAI{x: before}), OffsetAt(x))
+	}
+	if prev, ok := PrevLeap(x); !ok || prev.TAI != x || prev.Offset != 36 {
+		t.Errorf("PrevLeap = %+v, %v", prev, ok)
+	}
+	if !TAITime(x).Equal(negativeLeapMidnight) {
+		t.Errorf("TAITime(%v) = %v", x, TAITime(x))
+	}
+}
+
+func TestNegativeLeapSecondTimestamps(t *testing.T) {
+	withNegativeLeap(t)
+	tain := TAINfromTime(negativeLeapMidnight.Add(-12 * time.Hour))
+
+	if li := LeapIndicatorfromTAIN(tain); li != LeapDelete {
+		t.Errorf("leap indicator is %v", li)
+	}
+	if a := PTPAnnouncefromTAIN(tain); !a.Leap59 || a.Leap61 {
+		t.Errorf("PTP announce is %+v", a)
+	}
+	m := TAINMJD(tain, ScaleUTC)
+	if _, err := TAINfromMJD(MJD{Day: m.Day, Nano: 86399e9}, ScaleUTC); err == nil {
+		t.Error("TAINfromMJD accepted the removed second")
+	}
+
+	// a smeared clock loses the second over the window
+	s := Smear{Start: -1000 * time.Second, Duration: 1000 * time.Second}
+	end := TAINfromTime(negativeLeapMidnight)
+	if x := TAINfromSmearedTime(negativeLeapMidnight.Add(-500*time.Second), s); x != TAINAdd(end, -500*time.Second+500*time.Millisecond) {
+		t.Errorf("smeared midpoint is %v", x)
+	}
+}
+
+func TestNewLeapTableNegative(t *testing.T) {
+	midnight := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := NewLeapTable([]LeapSecond{{Begin: midnight, Offset: 9}}); err != nil {
+		t.Errorf("negative first leap second: %v", err)
+	}
+	if _, err := NewLeapTable([]LeapSecond{{Begin: midnight, Offset: 8}}); err == nil {
+		t.Error("leap of two seconds accepted")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import "fmt"
+
+// MJD is an exact Modified Julian Date: the day number and the
+// nanoseconds since its midnight. A UTC day with an inserted leap second
+// has 86401e9 nanoseconds. MJD and JD count days in UTC or TAI; for TT,
+// TCG, TCB and TDB use JulianDate, which JulianDatefromTAIN derives from
+// the MJD of a timestamp in TAI.
+type MJD struct {
+	Day  int64
+	Nano int64
+}
+
+// JD is an exact Julian Date: the day number and the nanoseconds since its
+// noon. A UTC day containing an inserted leap second has 86401e9
+// nanoseconds. See JulianDate for the floating point form.
+type JD struct {
+	Day  int64
+	Nano int64
+}
+
+const (
+	// mjdToJD is the JD day number of the noon of MJD day 0
+	mjdToJD = 2400001
+	// halfDay is 12 hours in nanoseconds
+	halfDay = 43200e9
+)
+
+// dayNanos returns the length of MJD day in scale in nanoseconds
+func dayNanos(day int64, scale Scale) int64 {
+	if scale == ScaleTAI {
+		return 86400e9
+	}
+	start := (day - mjdUnix) * 86400
+	return int64(labelFromUnix(start+86400)-labelFromUnix(start)) * 1e9
+}
+
+// TAINMJD returns the Modified Julian Date of t in scale
+func TAINMJD(t TAIN, scale Scale) MJD {
+	var unix int64
+	leap := false
+	if scale == ScaleTAI {
+		unix = int64(t.sec - tai64Epoch)
+	} else {
+		unix, leap = unixFromLabel(t.sec)
+	}
+
+	days := floorDiv(unix, 86400)
+	nano := (unix-days*86400)*1e9 + int64(t.nano)
+	if leap {
+		nano += 1e9
+	}
+	return MJD{Day: days + mjdUnix, Nano: nano}
+}
+
+// TAINfromMJD returns the TAIN timestamp of a Modified Julian Date in
+// scale, it fails when Nano is outside of the day
+func TAINfromMJD(m MJD, scale Scale) (TAIN, error) {
+	if m.Nano < 0 || m.Nano >= dayNanos(m.Day, scale) {
+		return TAIN{}, fmt.Errorf("MJD %d has no nanosecond %d in %v", m.Day, m.Nano, scale)
+	}
+
+	start := (m.Day - mjdUnix) * 86400
+	sec := tai64Epoch + uint64(start)
+	if scale != ScaleTAI {
+		sec = labelFromUnix(start)
+	}
+	return TAIN{sec: sec + uint64(m.Nano/1e9), nano: uint32(m.Nano % 1e9)}, nil
+}
+
+// TAIMJD returns the Modified Julian Date of t in scale
+func TAIMJD(t TAI, scale Scale) MJD {
+	return TAINMJD(TAIN{sec: t.x}, scale)
+}
+
+// TAIfromMJD returns the TAI timestamp of a Modified Julian Date in scale,
+// truncated to the second
+func TAIfromMJD(m MJD, scale Scale) (TAI, error) {
+	t, err := TAINfromMJD(m, scale)
+	return TAIfromTAIN(t), err
+}
+
+// Frac returns the fraction of the day of m in scale, in [0, 1) even on
+// days with a leap second
+func (m MJD) Frac(scale Scale) float64 {
+	return float64(m.Nano) / float64(dayNanos(m.Day, scale))
+}
+
+// JD returns m as a Julian Date in scale
+func (m MJD) JD(scale Scale) JD {
+	if m.Nano >= halfDay {
+		return JD{Day: m.Day + mjdToJD, Nano: m.Nano - halfDay}
+	}
+	return JD{Day: m.Day + mjdToJD - 1, Nano: m.Nano + dayNanos(m.Day-1, scale) - halfDay}
+}
+
+// MJD returns j as a Modified Julian Date in scale
+func (j JD) MJD(scale Scale) MJD {
+	day := j.Day - mjdToJD
+	if rest := dayNanos(day, scale) - halfDay; j.Nano >= rest {
+		return MJD{Day: day + 1, Nano: j.Nano - rest}
+	}
+	return MJD{Day: day, Nano: j.Nano + halfDay}
+}
+
+// Frac returns the fraction of the day of j in scale, in [0, 1) even on
+// days with a leap second
+func (j JD) Frac(scale Scale) float64 {
+	return float64(j.Nano) / float64(dayNanos(j.Day-mjdToJD, scale))
+}
+
+// TAINJD returns the Julian Date of t in scale
+func TAINJD(t TAIN, scale Scale) JD {
+	return TAINMJD(t, scale).JD(scale)
+}
+
+// TAINfromJD returns the TAIN timestamp of a Julian Date in scale, it fails
+// when Nano is outside of the day
+func TAINfromJD(j JD, scale Scale) (TAIN, error) {
+	day := j.Day - mjdToJD
+	if j.Nano < 0 || j.Nano >= dayNanos(day, scale) {
+		return TAIN{}, fmt.Errorf("JD %d has no nanosecond %d in %v", j.Day, j.Nano, scale)
+	}
+	return TAINfromMJD(j.MJD(scale), scale)
+}
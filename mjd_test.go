@@ -0,0 +1,114 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTAINMJD(t *testing.T) {
+	leap := TAINAdd(TAINfromTime(time.Date(2016, time.December, 31, 23, 59, 59, 250, time.UTC)), time.Second)
+	tests := []struct {
+		t     TAIN
+		scale Scale
+		mjd   MJD
+	}{
+		{TAINfromTime(time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)), ScaleUTC, MJD{40587, 0}},
+		{TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC)), ScaleUTC, MJD{58163, 70270e9 + 5}},
+		{TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC)), ScaleTAI, MJD{58163, 70307e9 + 5}},
+		{leap, ScaleUTC, MJD{57753, 86400e9 + 250}},
+		{TAINAdd(leap, time.Second), ScaleUTC, MJD{57754, 250}},
+		{TAINfromTime(time.Date(1858, time.November, 16, 12, 0, 0, 0, time.UTC)), ScaleUTC, MJD{-1, 43200e9}},
+	}
+	for _, tt := range tests {
+		if m := TAINMJD(tt.t, tt.scale); m != tt.mjd {
+			t.Errorf("TAINMJD(%v, %v) = %+v, expected %+v", tt.t, tt.scale, m, tt.mjd)
+		}
+		if x, err := TAINfromMJD(tt.mjd, tt.scale); err != nil || x != tt.t {
+			t.Errorf("TAINfromMJD(%+v, %v) = %v, %v, expected %v", tt.mjd, tt.scale, x, err, tt.t)
+		}
+	}
+}
+
+func TestTAINfromMJDErrors(t *testing.T) {
+	for _, tt := range []struct {
+		mjd   MJD
+		scale Scale
+	}{
+		{MJD{57753, -1}, ScaleUTC},
+		{MJD{57753, 86401e9}, ScaleUTC},
+		{MJD{57752, 86400e9}, ScaleUTC},
+		{MJD{57753, 86400e9}, ScaleTAI},
+	} {
+		if _, err := TAINfromMJD(tt.mjd, tt.scale); err == nil {
+			t.Errorf("TAINfromMJD(%+v, %v) succeeded", tt.mjd, tt.scale)
+		}
+	}
+}
+
+func TestTAIMJD(t *testing.T) {
+	x := TAIfromTime(time.Date(2018, time.February, 14, 0, 0, 0, 0, time.UTC))
+	m := TAIMJD(x, ScaleUTC)
+	if m != (MJD{58163, 0}) {
+		t.Errorf("TAIMJD = %+v", m)
+	}
+	if y, err := TAIfromMJD(MJD{58163, 500}, ScaleUTC); err != nil || y != x {
+		t.Errorf("TAIfromMJD = %v, %v", y, err)
+	}
+}
+
+func TestJD(t *testing.T) {
+	tests := []struct {
+		mjd   MJD
+		scale Scale
+		jd    JD
+	}{
+		{MJD{51544, 43200e9}, ScaleUTC, JD{2451545, 0}},
+		{MJD{51544, 0}, ScaleUTC, JD{2451544, 43200e9}},
+		// the JD day from noon 2016-12-31 has the leap second
+		{MJD{57753, 86400e9}, ScaleUTC, JD{2457754, 43200e9}},
+		{MJD{57754, 0}, ScaleUTC, JD{2457754, 43201e9}},
+		{MJD{57754, 0}, ScaleTAI, JD{2457754, 43200e9}},
+		{MJD{57754, 43200e9}, ScaleUTC, JD{2457755, 0}},
+	}
+	for _, tt := range tests {
+		if j := tt.mjd.JD(tt.scale); j != tt.jd {
+			t.Errorf("%+v.JD(%v) = %+v, expected %+v", tt.mjd, tt.scale, j, tt.jd)
+		}
+		if m := tt.jd.MJD(tt.scale); m != tt.mjd {
+			t.Errorf("%+v.MJD(%v) = %+v, expected %+v", tt.jd, tt.scale, m, tt.mjd)
+		}
+	}
+}
+
+func TestTAINJD(t *testing.T) {
+	tain := TAINfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	j := TAINJD(tain, ScaleUTC)
+	if j != (JD{2457754, 43201e9}) {
+		t.Errorf("TAINJD = %+v", j)
+	}
+	if x, err := TAINfromJD(j, ScaleUTC); err != nil || x != tain {
+		t.Errorf("TAINfromJD = %v, %v", x, err)
+	}
+	if _, err := TAINfromJD(JD{2457755, 86400e9}, ScaleUTC); err == nil {
+		t.Error("TAINfromJD accepted a nanosecond past the day")
+	}
+}
+
+func TestMJDFrac(t *testing.T) {
+	if f := (MJD{57753, 86400e9}).Frac(ScaleUTC); f >= 1 || f != 86400.0/86401 {
+		t.Errorf("leap second fraction is %v", f)
+	}
+	if f := (MJD{58163, 21600e9}).Frac(ScaleTAI); f != 0.25 {
+		t.Errorf("fraction is %v", f)
+	}
+	if f := (JD{2457754, 43200e9}).Frac(ScaleUTC); f != 43200.0/86401 {
+		t.Errorf("JD fraction is %v", f)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Monotonic hands out strictly increasing TAIN timestamps read from a
+// Clock, safe for concurrent use without locks. When the clock has not
+// advanced past the last timestamp handed out, because it was read twice
+// within the same nanosecond or it was stepped back, the nanoseconds of the
+// last timestamp are bumped by one instead.
+//
+// A timestamp returned by Next therefore leads the clock reading it was
+// derived from by at most the largest backward step the clock took plus
+// one nanosecond per call made while the clock stood still; Stats reports
+// the largest lead actually observed. Timestamps are tracked as
+// nanoseconds since 1970-01-01 00:00:00 TAI, which covers clocks between
+// 1970 and 2554.
+//
+// The zero value reads SystemClock.
+type Monotonic struct {
+	clock   Clock
+	last    atomic.Uint64
+	calls   atomic.Uint64
+	bumps   atomic.Uint64
+	maxLead atomic.Uint64
+}
+
+// MonotonicStats counts what a Monotonic generator did
+type MonotonicStats struct {
+	// Calls is the number of timestamps handed out
+	Calls uint64
+	// Bumps is the number of timestamps that had to be moved past the
+	// clock reading to keep them strictly increasing
+	Bumps uint64
+	// MaxLead is the largest distance a timestamp was moved past its
+	// clock reading
+	MaxLead time.Duration
+}
+
+// NewMonotonic returns a Monotonic generator reading c
+func NewMonotonic(c Clock) *Monotonic {
+	return &Monotonic{clock: c}
+}
+
+// Next returns a TAIN timestamp strictly greater than every timestamp
+// returned before by m
+func (m *Monotonic) Next() TAIN {
+	var now uint64
+	if m.clock == nil {
+		now = tainNanos(TAINNow())
+	} else {
+		now = tainNanos(m.clock.TAINNow())
+	}
+
+	for {
+		last := m.last.Load()
+		next := max(now, last+1)
+		if m.last.CompareAndSwap(last, next) {
+			m.calls.Add(1)
+			if next != now {
+				m.bumped(next - now)
+			}
+			return tainFromNanos(next)
+		}
+	}
+}
+
+// bumped records a timestamp moved lead nanoseconds past the clock
+func (m *Monotonic) bumped(lead uint64) {
+	m.bumps.Add(1)
+	for {
+		old := m.maxLead.Load()
+		if lead <= old || m.maxLead.CompareAndSwap(old, lead) {
+			return
+		}
+	}
+}
+
+// Stats returns the counters of m
+func (m *Monotonic) Stats() MonotonicStats {
+	return MonotonicStats{
+		Calls:   m.calls.Load(),
+		Bumps:   m.bumps.Load(),
+		MaxLead: time.Duration(m.maxLead.Load()),
+	}
+}
+
+// tainNanos returns the nanoseconds since 1970-01-01 00:00:00 TAI of t
+func tainNanos(t TAIN) uint64 {
+	return (t.sec-tai64Epoch)*1e9 + uint64(t.nano)
+}
+
+// tainFromNanos is the inverse of tainNanos
+func tainFromNanos(n uint64) TAIN {
+	return TAIN{sec: tai64Epoch + n/1e9, nano: uint32(n % 1e9)}
+}
@@ -0,0 +1,97 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonotonicBumps(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 999999999, time.UTC))
+	clock := &steppingClock{readings: []TAIN{
+		base,
+		base,
+		TAINAdd(base, -time.Second),
+		TAINAdd(base, time.Second),
+	}}
+	m := NewMonotonic(clock)
+
+	expected := []TAIN{
+		base,
+		TAINAdd(base, time.Nanosecond),
+		TAINAdd(base, 2*time.Nanosecond),
+		TAINAdd(base, time.Second),
+	}
+	for i, e := range expected {
+		if next := m.Next(); next != e {
+			t.Errorf("call %d returned %v, expected %v", i, next, e)
+		}
+	}
+
+	stats := m.Stats()
+	if stats.Calls != 4 || stats.Bumps != 2 || stats.MaxLead != time.Second+2*time.Nanosecond {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestMonotonicConcurrent(t *testing.T) {
+	const goroutines, calls = 8, 1000
+	m := NewMonotonic(FixedClock(TAINNow()))
+	results := make(chan TAIN, goroutines*calls)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := TAIN{}
+			for i := 0; i < calls; i++ {
+				next := m.Next()
+				if !tainLessOrEqual(prev, next) || prev == next {
+					t.Errorf("%v returned after %v", next, prev)
+				}
+				prev = next
+				results <- next
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	all := make([]TAIN, 0, goroutines*calls)
+	for r := range results {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool { return tainLessOrEqual(all[i], all[j]) })
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] {
+			t.Fatalf("%v returned twice", all[i])
+		}
+	}
+	if stats := m.Stats(); stats.Calls != goroutines*calls || stats.Bumps != goroutines*calls-1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestMonotonicZeroValue(t *testing.T) {
+	var m Monotonic
+	a, b := m.Next(), m.Next()
+	if !tainLessOrEqual(a, b) || a == b {
+		t.Errorf("%v returned after %v", b, a)
+	}
+}
+
+func TestTAINNanos(t *testing.T) {
+	tain := TAIN{sec: 0x400000005A848EAD, nano: 999999999}
+	if back := tainFromNanos(tainNanos(tain)); back != tain {
+		t.Errorf("%v round tripped to %v", tain, back)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strconv"
+)
+
+// ErrNTPUnsynchronized is returned for NTP timestamps whose leap
+// indicator says the clock is not synchronized
+var ErrNTPUnsynchronized = errors.New("NTP clock is not synchronized")
+
+// ntpUnixOffset is the number of seconds from 1900-01-01 to 1970-01-01
+const ntpUnixOffset = 2208988800
+
+// NTPTimestamp is a 64 bit NTP timestamp: 32 bits of UTC seconds since
+// the start of its era followed by 32 bits of fraction. Era 0 started at
+// 1900-01-01 and era 1 at 2036-02-07.
+type NTPTimestamp uint64
+
+// NTPDate is an RFC 5905 128 bit NTP datestamp
+type NTPDate struct {
+	Era      int32
+	Offset   uint32
+	Fraction uint64
+}
+
+// LeapIndicator is the leap warning of an NTP packet header
+type LeapIndicator uint8
+
+const (
+	// LeapNone announces no leap second
+	LeapNone LeapIndicator = iota
+	// LeapInsert announces that the last minute of the day has 61 seconds
+	LeapInsert
+	// LeapDelete announces that the last minute of the day has 59 seconds
+	LeapDelete
+	// LeapAlarm says that the clock is not synchronized
+	LeapAlarm
+)
+
+func (li LeapIndicator) String() string {
+	switch li {
+	case LeapNone:
+		return "none"
+	case LeapInsert:
+		return "insert"
+	case LeapDelete:
+		return "delete"
+	case LeapAlarm:
+		return "alarm"
+	default:
+		return "LeapIndicator(" + strconv.Itoa(int(li)) + ")"
+	}
+}
+
+// NTPLeapIndicator returns the leap indicator from the first byte of an
+// NTP packet header
+func NTPLeapIndicator(header byte) LeapIndicator {
+	return LeapIndicator(header >> 6)
+}
+
+// LeapIndicatorfromTAIN returns the leap indicator an NTP server sends at
+// t: LeapInsert or LeapDelete during a UTC day that ends with a leap
+// second, LeapNone otherwise
+func LeapIndicatorfromTAIN(t TAIN) LeapIndicator {
+	day := TAINMJD(t, ScaleUTC).Day
+	switch dayNanos(day, ScaleUTC) {
+	case 86401e9:
+		return LeapInsert
+	case 86399e9:
+		return LeapDelete
+	default:
+		return LeapNone
+	}
+}
+
+// ntpSeconds returns the UTC seconds since 1900-01-01 of t, an inserted
+// leap second repeats the second before it as NTP does
+func ntpSeconds(t TAIN) int64 {
+	unix, _ := unixFromLabel(t.sec)
+	return unix + ntpUnixOffset
+}
+
+// tainFromNTPSeconds returns the TAIN timestamp of UTC seconds since
+// 1900-01-01 and nanoseconds
+func tainFromNTPSeconds(sec int64, nano uint32) TAIN {
+	return TAIN{sec: labelFromUnix(sec - ntpUnixOffset), nano: nano}
+}
+
+// NTPfromTAIN returns the NTP timestamp of t. Nanoseconds of a second or
+// more are carried into the seconds.
+func NTPfromTAIN(t TAIN) NTPTimestamp {
+	t = tainNormalize(t)
+	frac := (uint64(t.nano)<<32 + 1e9 - 1) / 1e9
+	return NTPTimestamp(uint64(uint32(ntpSeconds(t)))<<32 | frac)
+}
+
+// TAINfromNTP returns the TAIN timestamp of an NTP timestamp in the era
+// that puts it closest to pivot. It fails with ErrNTPUnsynchronized when
+// li is LeapAlarm.
+//
+// LeapInsert and LeapDelete are otherwise ignored. NTP repeats 23:59:59
+// during an inserted leap second and keeps the indicator set throughout,
+// so a single timestamp can not tell the two seconds apart; both are
+// returned as the first 23:59:59. Callers that need the leap second must
+// track it across samples, e.g. by noticing the timestamps step back.
+func TAINfromNTP(ts NTPTimestamp, li LeapIndicator, pivot TAIN) (TAIN, error) {
+	if li == LeapAlarm {
+		return TAIN{}, ErrNTPUnsynchronized
+	}
+	p := ntpSeconds(pivot)
+	sec := p + int64(int32(uint32(ts>>32)-uint32(p)))
+	nano := uint32((uint64(uint32(ts)) * 1e9) >> 32)
+	return tainFromNTPSeconds(sec, nano), nil
+}
+
+// NTPDatefromTAIN returns the NTP datestamp of t. Nanoseconds of a second
+// or more are carried into the seconds.
+func NTPDatefromTAIN(t TAIN) NTPDate {
+	t = tainNormalize(t)
+	sec := ntpSeconds(t)
+	frac, rem := bits.Div64(uint64(t.nano), 0, 1e9)
+	if rem != 0 {
+		frac++
+	}
+	return NTPDate{
+		Era:      int32(floorDiv(sec, 1<<32)),
+		Offset:   uint32(sec),
+		Fraction: frac,
+	}
+}
+
+// TAINfromNTPDate returns the TAIN timestamp of an NTP datestamp
+func TAINfromNTPDate(d NTPDate) TAIN {
+	sec := int64(d.Era)<<32 + int64(d.Offset)
+	nano, _ := bits.Mul64(d.Fraction, 1e9)
+	return tainFromNTPSeconds(sec, uint32(nano))
+}
+
+// NTPPack packs an NTP timestamp into a byte array of size NTPLength
+func NTPPack(ts NTPTimestamp) []byte {
+	result := make([]byte, NTPLength)
+	binary.BigEndian.PutUint64(result, uint64(ts))
+	return result
+}
+
+// NTPUnpack unpacks an NTP timestamp from a byte array of size NTPLength
+func NTPUnpack(s []byte) NTPTimestamp {
+	return NTPTimestamp(binary.BigEndian.Uint64(s))
+}
+
+// NTPDatePack packs an NTP datestamp into a byte array of size
+// NTPDateLength
+func NTPDatePack(d NTPDate) []byte {
+	result := make([]byte, NTPDateLength)
+	binary.BigEndian.PutUint32(result, uint32(d.Era))
+	binary.BigEndian.PutUint32(result[4:], d.Offset)
+	binary.BigEndian.PutUint64(result[8:], d.Fraction)
+	return result
+}
+
+// NTPDateUnpack unpacks an NTP datestamp from a byte array of size
+// NTPDateLength
+func NTPDateUnpack(s []byte) NTPDate {
+	return NTPDate{
+		Era:      int32(binary.BigEndian.Uint32(s)),
+		Offset:   binary.BigEndian.Uint32(s[4:]),
+		Fraction: binary.BigEndian.Uint64(s[8:]),
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNTPfromTAIN(t *testing.T) {
+	tests := []struct {
+		tm time.Time
+		ts NTPTimestamp
+	}{
+		{time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), 0x83AA7E80_00000000},
+		{time.Date(1970, time.January, 1, 0, 0, 0, 500000000, time.UTC), 0x83AA7E80_80000000},
+		{time.Date(2036, time.February, 7, 6, 28, 16, 0, time.UTC), 0},
+		{time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC), 0xDE2F0CFE_00000000},
+	}
+	for _, tt := range tests {
+		tain := TAINfromTime(tt.tm)
+		if ts := NTPfromTAIN(tain); ts != tt.ts {
+			t.Errorf("NTPfromTAIN(%v) = %#x, expected %#x", tt.tm, uint64(ts), uint64(tt.ts))
+		}
+		if x, err := TAINfromNTP(tt.ts, LeapNone, tain); err != nil || x != tain {
+			t.Errorf("TAINfromNTP(%#x) = %v, %v, expected %v", uint64(tt.ts), x, err, tain)
+		}
+	}
+}
+
+func TestTAINfromNTPEra(t *testing.T) {
+	ts := NTPfromTAIN(TAINfromTime(time.Date(2036, time.February, 7, 6, 28, 20, 0, time.UTC)))
+	tests := []struct {
+		pivot time.Time
+		tm    time.Time
+	}{
+		{time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2036, time.February, 7, 6, 28, 20, 0, time.UTC)},
+		{time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(1900, time.January, 1, 0, 0, 4, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		x, err := TAINfromNTP(ts, LeapNone, TAINfromTime(tt.pivot))
+		if err != nil || !TAINTime(x).Equal(tt.tm) {
+			t.Errorf("pivot %v returned %v, %v, expected %v", tt.pivot, TAINTime(x), err, tt.tm)
+		}
+	}
+	if _, err := TAINfromNTP(ts, LeapAlarm, TAINNow()); !errors.Is(err, ErrNTPUnsynchronized) {
+		t.Errorf("LeapAlarm returned %v", err)
+	}
+}
+
+func TestNTPNanosecondRoundTrip(t *testing.T) {
+	base := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 0, time.UTC))
+	for _, n := range []time.Duration{1, 2, 3, 499999999, 500000001, 999999999} {
+		tain := TAINAdd(base, n)
+		if x, _ := TAINfromNTP(NTPfromTAIN(tain), LeapNone, base); x != tain {
+			t.Errorf("timestamp round trip of %v returned %v", tain, x)
+		}
+		if x := TAINfromNTPDate(NTPDatefromTAIN(tain)); x != tain {
+			t.Errorf("datestamp round trip of %v returned %v", tain, x)
+		}
+	}
+}
+
+func TestNTPLeapSecond(t *testing.T) {
+	before := TAINfromTime(time.Date(2016, time.December, 31, 23, 59, 59, 0, time.UTC))
+	leap := TAINAdd(before, time.Second)
+	if NTPfromTAIN(leap) != NTPfromTAIN(before) {
+		t.Errorf("leap second does not repeat the second before it")
+	}
+	// the leap indicator does not change the result
+	for _, li := range []LeapIndicator{LeapNone, LeapInsert, LeapDelete} {
+		for _, tain := range []TAIN{before, TAINAdd(leap, 500*time.Millisecond)} {
+			x, err := TAINfromNTP(NTPfromTAIN(tain), li, leap)
+			if err != nil || x != (TAIN{sec: before.sec, nano: tain.nano}) {
+				t.Errorf("%v with %v read back as %v, %v", tain, li, x, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		tm time.Time
+		li LeapIndicator
+	}{
+		{time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC), LeapInsert},
+		{time.Date(2016, time.December, 31, 23, 59, 59, 0, time.UTC), LeapInsert},
+		{time.Date(2016, time.December, 30, 23, 59, 59, 0, time.UTC), LeapNone},
+		{time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), LeapNone},
+	}
+	for _, tt := range tests {
+		if li := LeapIndicatorfromTAIN(TAINfromTime(tt.tm)); li != tt.li {
+			t.Errorf("leap indicator at %v is %v, expected %v", tt.tm, li, tt.li)
+		}
+	}
+	if li := LeapIndicatorfromTAIN(leap); li != LeapInsert {
+		t.Errorf("leap indicator during the leap second is %v", li)
+	}
+}
+
+func TestNTPNanosecondsOutOfRange(t *testing.T) {
+	tain, err := TAINfromString("@4000000000000000FFFFFFFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	carried := TAIN{sec: tai64Epoch + 4, nano: 294967295}
+	if NTPfromTAIN(tain) != NTPfromTAIN(carried) {
+		t.Errorf("NTPfromTAIN(%v) = %#x, expected %#x", tain, NTPfromTAIN(tain), NTPfromTAIN(carried))
+	}
+	if NTPDatefromTAIN(tain) != NTPDatefromTAIN(carried) {
+		t.Errorf("NTPDatefromTAIN(%v) = %+v, expected %+v", tain, NTPDatefromTAIN(tain), NTPDatefromTAIN(carried))
+	}
+}
+
+func TestNTPLeapIndicator(t *testing.T) {
+	for b, li := range map[byte]LeapIndicator{0x24: LeapNone, 0x64: LeapInsert, 0xA4: LeapDelete, 0xE3: LeapAlarm} {
+		if x := NTPLeapIndicator(b); x != li {
+			t.Errorf("NTPLeapIndicator(%#x) = %v, expected %v", b, x, li)
+		}
+	}
+	if s := LeapIndicator(7).String(); s != "LeapIndicator(7)" {
+		t.Errorf("String() = %s", s)
+	}
+}
+
+func TestNTPDate(t *testing.T) {
+	tests := []struct {
+		tm time.Time
+		d  NTPDate
+	}{
+		{time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC), NTPDate{}},
+		{time.Date(2036, time.February, 7, 6, 28, 16, 500000000, time.UTC), NTPDate{Era: 1, Fraction: 1 << 63}},
+		{time.Date(1899, time.December, 31, 23, 59, 59, 0, time.UTC), NTPDate{Era: -1, Offset: 1<<32 - 1}},
+	}
+	for _, tt := range tests {
+		tain := TAINfromTime(tt.tm)
+		if d := NTPDatefromTAIN(tain); d != tt.d {
+			t.Errorf("NTPDatefromTAIN(%v) = %+v, expected %+v", tt.tm, d, tt.d)
+		}
+		if x := TAINfromNTPDate(tt.d); x != tain {
+			t.Errorf("TAINfromNTPDate(%+v) = %v, expected %v", tt.d, x, tain)
+		}
+	}
+}
+
+func TestNTPPackUnpack(t *testing.T) {
+	ts := NTPTimestamp(0xDE2F0AB6_80000000)
+	b := NTPPack(ts)
+	if !bytes.Equal(b, []byte{0xDE, 0x2F, 0x0A, 0xB6, 0x80, 0, 0, 0}) || NTPUnpack(b) != ts {
+		t.Errorf("NTPPack = %x", b)
+	}
+
+	d := NTPDate{Era: -1, Offset: 0xDE2F0AB6, Fraction: 1 << 63}
+	b = NTPDatePack(d)
+	if len(b) != NTPDateLength || NTPDateUnpack(b) != d {
+		t.Errorf("NTPDatePack = %x", b)
+	}
+}
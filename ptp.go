@@ -0,0 +1,134 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrPTPOffsetMismatch is returned when an announced UTC offset does
+	// not match the leap second table
+	ErrPTPOffsetMismatch = errors.New("PTP UTC offset does not match the leap second table")
+	// ErrPTPLeapMismatch is returned when announced leap flags do not
+	// match the leap second table
+	ErrPTPLeapMismatch = errors.New("PTP leap flags do not match the leap second table")
+)
+
+// ptpSecondsMask keeps the 48 bits of PTP seconds
+const ptpSecondsMask = 1<<48 - 1
+
+// PTPTimestamp is an IEEE 1588 timestamp on the PTP timescale, which is
+// TAI counted from 1970-01-01T00:00:00 TAI
+type PTPTimestamp struct {
+	// Seconds has 48 significant bits
+	Seconds     uint64
+	Nanoseconds uint32
+}
+
+// PTPfromTAIN returns the PTP timestamp of t, which must not be before
+// the PTP epoch
+func PTPfromTAIN(t TAIN) PTPTimestamp {
+	return PTPTimestamp{Seconds: (t.sec - tai64Epoch) & ptpSecondsMask, Nanoseconds: t.nano}
+}
+
+// TAINfromPTP returns the TAIN timestamp of a PTP timestamp
+func TAINfromPTP(p PTPTimestamp) TAIN {
+	return TAIN{sec: tai64Epoch + p.Seconds&ptpSecondsMask, nano: p.Nanoseconds}
+}
+
+// PTPPack packs a PTP timestamp into its wire format, a byte array of size
+// PTPLength
+func PTPPack(p PTPTimestamp) []byte {
+	result := make([]byte, PTPLength)
+	binary.BigEndian.PutUint16(result, uint16(p.Seconds>>32))
+	binary.BigEndian.PutUint32(result[2:], uint32(p.Seconds))
+	binary.BigEndian.PutUint32(result[6:], p.Nanoseconds)
+	return result
+}
+
+// PTPUnpack unpacks a PTP timestamp from a byte array of size PTPLength
+func PTPUnpack(s []byte) PTPTimestamp {
+	return PTPTimestamp{
+		Seconds:     uint64(binary.BigEndian.Uint16(s))<<32 | uint64(binary.BigEndian.Uint32(s[2:])),
+		Nanoseconds: binary.BigEndian.Uint32(s[6:]),
+	}
+}
+
+// Announce flagField bits of the second octet
+const (
+	ptpFlagLeap61      = 0x01
+	ptpFlagLeap59      = 0x02
+	ptpFlagOffsetValid = 0x04
+)
+
+// PTPAnnounce is the UTC information of a PTP Announce message
+type PTPAnnounce struct {
+	// CurrentUTCOffset is TAI - UTC in seconds
+	CurrentUTCOffset int16
+	// OffsetValid is the currentUtcOffsetValid flag
+	OffsetValid bool
+	// Leap61 announces that the last minute of the UTC day has 61 seconds
+	Leap61 bool
+	// Leap59 announces that the last minute of the UTC day has 59 seconds
+	Leap59 bool
+}
+
+// PTPAnnouncefromFlags returns the UTC information of an Announce message
+// from its flagField, read as a big endian uint16, and currentUtcOffset
+func PTPAnnouncefromFlags(flags uint16, offset int16) PTPAnnounce {
+	return PTPAnnounce{
+		CurrentUTCOffset: offset,
+		OffsetValid:      flags&ptpFlagOffsetValid != 0,
+		Leap61:           flags&ptpFlagLeap61 != 0,
+		Leap59:           flags&ptpFlagLeap59 != 0,
+	}
+}
+
+// PTPAnnouncefromTAIN returns the UTC information a grandmaster following
+// the leap second table announces at t, leap flags are set during the whole
+// UTC day ending with a leap second
+func PTPAnnouncefromTAIN(t TAIN) PTPAnnounce {
+	unix, _ := unixFromLabel(t.sec)
+	li := LeapIndicatorfromTAIN(t)
+	return PTPAnnounce{
+		CurrentUTCOffset: int16(utcOffset(unix)),
+		OffsetValid:      true,
+		Leap61:           li == LeapInsert,
+		Leap59:           li == LeapDelete,
+	}
+}
+
+// Time returns the UTC time of a PTP timestamp using the announced offset
+func (a PTPAnnounce) Time(p PTPTimestamp) time.Time {
+	sec := int64(p.Seconds&ptpSecondsMask) - int64(a.CurrentUTCOffset)
+	return time.Unix(sec, int64(p.Nanoseconds)).UTC()
+}
+
+// Check cross-checks a with the leap second table at t. A valid offset
+// must match the table, leap flags that are set must match the table and
+// missing flags are reported during the last 12 hours before a leap second.
+// Errors wrap ErrPTPOffsetMismatch or ErrPTPLeapMismatch.
+func (a PTPAnnounce) Check(t TAIN) error {
+	want := PTPAnnouncefromTAIN(t)
+	if a.OffsetValid && a.CurrentUTCOffset != want.CurrentUTCOffset {
+		return fmt.Errorf("%w: announced %d, expected %d", ErrPTPOffsetMismatch, a.CurrentUTCOffset, want.CurrentUTCOffset)
+	}
+
+	late := TAINMJD(t, ScaleUTC).Nano >= halfDay
+	switch {
+	case a.Leap61 && !want.Leap61, a.Leap59 && !want.Leap59:
+		return fmt.Errorf("%w: announced a leap second the table does not have", ErrPTPLeapMismatch)
+	case late && (want.Leap61 && !a.Leap61 || want.Leap59 && !a.Leap59):
+		return fmt.Errorf("%w: the table has a leap second at the end of the day", ErrPTPLeapMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPTPfromTAIN(t *testing.T) {
+	// 2018-02-14T19:31:10Z is 1518636707 seconds on the PTP timescale
+	tain := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC))
+	p := PTPfromTAIN(tain)
+	if p != (PTPTimestamp{Seconds: 1518636707, Nanoseconds: 5}) {
+		t.Errorf("PTPfromTAIN = %+v", p)
+	}
+	if x := TAINfromPTP(p); x != tain {
+		t.Errorf("TAINfromPTP = %v, expected %v", x, tain)
+	}
+	if p := PTPfromTAIN(TAINUnpack([]byte{0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})); p != (PTPTimestamp{}) {
+		t.Errorf("PTP epoch is %+v", p)
+	}
+}
+
+func TestPTPPackUnpack(t *testing.T) {
+	p := PTPTimestamp{Seconds: 0x123456789ABC, Nanoseconds: 999999999}
+	b := PTPPack(p)
+	expected := []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0x3B, 0x9A, 0xC9, 0xFF}
+	if !bytes.Equal(b, expected) {
+		t.Errorf("PTPPack = %x, expected %x", b, expected)
+	}
+	if x := PTPUnpack(b); x != p {
+		t.Errorf("PTPUnpack = %+v", x)
+	}
+}
+
+func TestPTPAnnouncefromFlags(t *testing.T) {
+	a := PTPAnnouncefromFlags(0x0035, 37)
+	if a != (PTPAnnounce{CurrentUTCOffset: 37, OffsetValid: true, Leap61: true}) {
+		t.Errorf("PTPAnnouncefromFlags = %+v", a)
+	}
+	a = PTPAnnouncefromFlags(0x0002, 36)
+	if a != (PTPAnnounce{CurrentUTCOffset: 36, Leap59: true}) {
+		t.Errorf("PTPAnnouncefromFlags = %+v", a)
+	}
+}
+
+func TestPTPAnnounceTime(t *testing.T) {
+	tm := time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC)
+	p := PTPfromTAIN(TAINfromTime(tm))
+	if x := (PTPAnnounce{CurrentUTCOffset: 37}).Time(p); !x.Equal(tm) {
+		t.Errorf("Time = %v, expected %v", x, tm)
+	}
+}
+
+func TestPTPAnnounceCheck(t *testing.T) {
+	noon := TAINfromTime(time.Date(2016, time.December, 31, 12, 0, 0, 0, time.UTC))
+	morning := TAINfromTime(time.Date(2016, time.December, 31, 6, 0, 0, 0, time.UTC))
+	after := TAINfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		a   PTPAnnounce
+		t   TAIN
+		err error
+	}{
+		{PTPAnnouncefromTAIN(noon), noon, nil},
+		{PTPAnnounce{CurrentUTCOffset: 36, OffsetValid: true}, morning, nil},
+		{PTPAnnounce{CurrentUTCOffset: 36, OffsetValid: true}, noon, ErrPTPLeapMismatch},
+		{PTPAnnounce{CurrentUTCOffset: 36, OffsetValid: true, Leap61: true}, after, ErrPTPOffsetMismatch},
+		{PTPAnnounce{CurrentUTCOffset: 37, OffsetValid: true, Leap61: true}, after, ErrPTPLeapMismatch},
+		{PTPAnnounce{CurrentUTCOffset: 36, OffsetValid: true, Leap59: true}, noon, ErrPTPLeapMismatch},
+		{PTPAnnounce{CurrentUTCOffset: 0, Leap61: true}, noon, nil},
+		{PTPAnnounce{CurrentUTCOffset: 37, OffsetValid: true}, after, nil},
+	}
+	for i, tt := range tests {
+		if err := tt.a.Check(tt.t); !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("case %d: Check returned %v, expected %v", i, err, tt.err)
+		}
+	}
+
+	if a := PTPAnnouncefromTAIN(noon); a != (PTPAnnounce{CurrentUTCOffset: 36, OffsetValid: true, Leap61: true}) {
+		t.Errorf("PTPAnnouncefromTAIN = %+v", a)
+	}
+}
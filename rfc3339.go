@@ -0,0 +1,240 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RFC3339Mode selects how strictly RFC 3339 timestamps are parsed
+type RFC3339Mode int
+
+const (
+	// RFC3339Strict accepts only the RFC 3339 grammar and second 60 only
+	// at an inserted leap second
+	RFC3339Strict RFC3339Mode = iota
+	// RFC3339Lenient also accepts a lower case 't' and 'z', a space
+	// between date and time, a comma before fractional seconds and second
+	// 60 at the end of any minute, which is read as the following second
+	RFC3339Lenient
+)
+
+// TAINAppendRFC3339 appends the RFC 3339 representation of a TAIN
+// timestamp in UTC to b, leap seconds show as second 60
+func TAINAppendRFC3339(b []byte, t TAIN) []byte {
+	return TAINAppendFormat(b, t, time.RFC3339Nano, ScaleUTC)
+}
+
+// TAINFormatRFC3339 returns the RFC 3339 representation of a TAIN
+// timestamp in UTC, leap seconds show as second 60
+func TAINFormatRFC3339(t TAIN) string {
+	var buf [40]byte
+	return string(TAINAppendRFC3339(buf[:0], t))
+}
+
+// TAINParseRFC3339 parses an RFC 3339 timestamp into a TAIN timestamp
+// using the leap second table
+func TAINParseRFC3339(s string, mode RFC3339Mode) (TAIN, error) {
+	p := rfc3339Parser{value: s, lenient: mode == RFC3339Lenient}
+	ct, offset, err := p.parse()
+	if err != nil {
+		return TAIN{}, err
+	}
+
+	sec, ok := ct.label(ScaleUTC, offset)
+	if !ok && p.lenient && ct.second == 60 {
+		ct.second = 59
+		sec, ok = ct.label(ScaleUTC, offset)
+		sec++
+	}
+	if !ok {
+		return TAIN{}, p.errorf(": second out of range")
+	}
+	return TAIN{sec: sec, nano: uint32(ct.nano)}, nil
+}
+
+// rfc3339Parser holds the state of parsing an RFC 3339 timestamp
+type rfc3339Parser struct {
+	value   string
+	rest    string
+	lenient bool
+}
+
+func (p *rfc3339Parser) errorf(msg string) error {
+	return &time.ParseError{Layout: time.RFC3339, Value: p.value, Message: msg}
+}
+
+// num parses a fixed width field followed by sep, if sep is not 0
+func (p *rfc3339Parser) num(n, lo, hi int, sep byte) (int, error) {
+	x, rest, err := getnum(p.rest, n, true)
+	if err = inRange(x, lo, hi, err); err != nil {
+		return 0, p.errorf(": cannot parse " + strconv.Quote(p.rest))
+	}
+	if sep != 0 {
+		if rest == "" || rest[0] != sep {
+			return 0, p.errorf(": cannot parse " + strconv.Quote(rest))
+		}
+		rest = rest[1:]
+	}
+	p.rest = rest
+	return x, nil
+}
+
+// parse parses the whole timestamp into a caltime and its offset
+func (p *rfc3339Parser) parse() (caltime, int, error) {
+	p.rest = p.value
+	ct, err := p.date()
+	if err == nil {
+		err = p.separator()
+	}
+	if err == nil {
+		err = p.clock(&ct)
+	}
+	if err != nil {
+		return ct, 0, err
+	}
+
+	offset, err := p.offset()
+	if err == nil && p.rest != "" {
+		err = p.errorf(": extra text: " + strconv.Quote(p.rest))
+	}
+	return ct, offset, err
+}
+
+// date parses full-date
+func (p *rfc3339Parser) date() (caltime, error) {
+	var ct caltime
+	var month int
+	var err error
+	if ct.year, err = p.num(4, 0, 9999, '-'); err != nil {
+		return ct, err
+	}
+	if month, err = p.num(2, 1, 12, '-'); err != nil {
+		return ct, err
+	}
+	if ct.day, err = p.num(2, 1, 31, 0); err != nil {
+		return ct, err
+	}
+
+	ct.month = time.Month(month)
+	tm := time.Date(ct.year, ct.month, ct.day, 0, 0, 0, 0, time.UTC)
+	if tm.Day() != ct.day {
+		return ct, p.errorf(": day out of range")
+	}
+	return ct, nil
+}
+
+// separator parses the separator between full-date and full-time
+func (p *rfc3339Parser) separator() error {
+	if p.rest != "" {
+		switch c := p.rest[0]; {
+		case c == 'T', p.lenient && (c == 't' || c == ' '):
+			p.rest = p.rest[1:]
+			return nil
+		}
+	}
+	return p.errorf(": cannot parse " + strconv.Quote(p.rest) + ` as "T"`)
+}
+
+// clock parses partial-time
+func (p *rfc3339Parser) clock(ct *caltime) error {
+	var err error
+	if ct.hour, err = p.num(2, 0, 23, ':'); err != nil {
+		return err
+	}
+	if ct.minute, err = p.num(2, 0, 59, ':'); err != nil {
+		return err
+	}
+	if ct.second, err = p.num(2, 0, 60, 0); err != nil {
+		return err
+	}
+
+	if p.rest != "" && (p.rest[0] == '.' || p.lenient && p.rest[0] == ',') {
+		p.rest, err = parseFrac(ct, p.rest)
+	}
+	return err
+}
+
+// offset parses time-offset and returns it in seconds east of UTC
+func (p *rfc3339Parser) offset() (int, error) {
+	if p.rest == "" {
+		return 0, p.errorf(": missing time offset")
+	}
+
+	switch c := p.rest[0]; {
+	case c == 'Z', p.lenient && c == 'z':
+		p.rest = p.rest[1:]
+		return 0, nil
+	case c != '+' && c != '-':
+		return 0, p.errorf(": cannot parse " + strconv.Quote(p.rest) + ` as "Z07:00"`)
+	}
+
+	sign := p.rest[0]
+	p.rest = p.rest[1:]
+	hh, err := p.num(2, 0, 23, ':')
+	if err != nil {
+		return 0, err
+	}
+	mm, err := p.num(2, 0, 59, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := hh*3600 + mm*60
+	if sign == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// getnum parses an unsigned decimal of up to n digits, exactly n digits
+// if fixed is set
+func getnum(value string, n int, fixed bool) (int, string, error) {
+	i := 0
+	for i < n && i < len(value) && isDigit(value[i]) {
+		i++
+	}
+	if i == 0 || (fixed && i != n) {
+		return 0, value, strconv.ErrSyntax
+	}
+	x, _ := strconv.Atoi(value[:i])
+	return x, value[i:], nil
+}
+
+// inRange returns err if it is set or x is outside [lo, hi]
+func inRange(x, lo, hi int, err error) error {
+	if err == nil && (x < lo || x > hi) {
+		return strconv.ErrRange
+	}
+	return err
+}
+
+// parseFrac parses the fractional seconds at the start of value if there
+// are any, digits beyond nanoseconds are dropped
+func parseFrac(ct *caltime, value string) (string, error) {
+	if len(value) < 2 || (value[0] != '.' && value[0] != ',') || !isDigit(value[1]) {
+		return value, nil
+	}
+	n := 1
+	for n+1 < len(value) && isDigit(value[n+1]) {
+		n++
+	}
+
+	digits := value[1 : n+1]
+	if len(digits) > 9 {
+		digits = digits[:9]
+	}
+	ct.nano, _ = strconv.Atoi(digits + strings.Repeat("0", 9-len(digits)))
+	return value[n+1:], nil
+}
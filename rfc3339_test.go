@@ -0,0 +1,77 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTAINParseRFC3339(t *testing.T) {
+	midnight := labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+	dec30 := labelFromUnix(time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC).Unix())
+	tests := []struct {
+		value    string
+		mode     RFC3339Mode
+		expected TAIN
+		ok       bool
+	}{
+		{"2016-12-31T23:59:60Z", RFC3339Strict, TAIN{sec: midnight - 1}, true},
+		{"2016-12-31T23:59:60.999999999Z", RFC3339Strict, TAIN{sec: midnight - 1, nano: 999999999}, true},
+		{"2016-12-31T18:59:60-05:00", RFC3339Strict, TAIN{sec: midnight - 1}, true},
+		{"2017-01-01T00:00:00Z", RFC3339Strict, TAIN{sec: midnight}, true},
+		{"2017-01-01T00:00:00-00:00", RFC3339Strict, TAIN{sec: midnight}, true},
+		{"2016-12-30T23:59:60Z", RFC3339Strict, TAIN{}, false},
+		{"2016-12-30T23:59:60Z", RFC3339Lenient, TAIN{sec: dec30}, true},
+		{"2016-12-31t23:59:60.5z", RFC3339Strict, TAIN{}, false},
+		{"2016-12-31t23:59:60.5z", RFC3339Lenient, TAIN{sec: midnight - 1, nano: 500000000}, true},
+		{"2016-12-31 23:59:60,5Z", RFC3339Strict, TAIN{}, false},
+		{"2016-12-31 23:59:60,5Z", RFC3339Lenient, TAIN{sec: midnight - 1, nano: 500000000}, true},
+		{"2016-12-31T23:59:61Z", RFC3339Lenient, TAIN{}, false},
+		{"2016-12-31T23:59:59", RFC3339Strict, TAIN{}, false},
+		{"2016-12-31T23:59:59.Z", RFC3339Strict, TAIN{}, false},
+		{"2016-12-31T23:59Z", RFC3339Lenient, TAIN{}, false},
+		{"2016-02-30T00:00:00Z", RFC3339Lenient, TAIN{}, false},
+		{"2016-12-31T23:59:59+0100", RFC3339Strict, TAIN{}, false},
+		{"2016-12-31T23:59:59Z extra", RFC3339Strict, TAIN{}, false},
+	}
+
+	for _, tc := range tests {
+		result, err := TAINParseRFC3339(tc.value, tc.mode)
+		switch {
+		case tc.ok && err != nil:
+			t.Errorf("TAINParseRFC3339(%q) failed: %v", tc.value, err)
+		case !tc.ok && err == nil:
+			t.Errorf("TAINParseRFC3339(%q) = %v, expected an error", tc.value, result)
+		case result != tc.expected:
+			t.Errorf("TAINParseRFC3339(%q) = %v, expected %v", tc.value, result, tc.expected)
+		}
+	}
+}
+
+func TestTAINFormatRFC3339(t *testing.T) {
+	midnight := labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+	tests := []struct {
+		tain     TAIN
+		expected string
+	}{
+		{TAIN{sec: midnight - 1}, "2016-12-31T23:59:60Z"},
+		{TAIN{sec: midnight - 1, nano: 250000000}, "2016-12-31T23:59:60.25Z"},
+		{TAIN{sec: midnight}, "2017-01-01T00:00:00Z"},
+	}
+
+	for _, tc := range tests {
+		s := TAINFormatRFC3339(tc.tain)
+		if s != tc.expected {
+			t.Errorf("TAINFormatRFC3339(%v) = %q, expected %q", tc.tain, s, tc.expected)
+		}
+		if back, err := TAINParseRFC3339(s, RFC3339Strict); err != nil || back != tc.tain {
+			t.Errorf("%q parsed back as %v, %v", s, back, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import "time"
+
+// "right" Unix time, used by hosts with right/ zoneinfo, counts every
+// second including leap seconds: it is TAI - 10s since 1970-01-01, so a
+// right time_t is a TAI64 label minus TAICONST.
+
+// TAIRight returns the right Unix time of a TAI timestamp
+func TAIRight(t TAI) int64 {
+	return int64(t.x - TAICONST)
+}
+
+// TAIfromRight returns the TAI timestamp of a right Unix time
+func TAIfromRight(right int64) TAI {
+	return TAI{x: TAICONST + uint64(right)}
+}
+
+// TAINRightTime returns a TAIN timestamp as a time.Time whose Unix time is
+// on the right scale, as time.Now returns on such hosts
+func TAINRightTime(t TAIN) time.Time {
+	return time.Unix(int64(t.sec-TAICONST), int64(t.nano)).UTC()
+}
+
+// TAINfromRightTime returns the TAIN timestamp of a time.Time whose Unix
+// time is on the right scale
+func TAINfromRightTime(t time.Time) TAIN {
+	return TAIN{sec: TAICONST + uint64(t.Unix()), nano: uint32(t.Nanosecond())}
+}
+
+// RightfromPosix returns the right Unix time of a POSIX Unix time using the
+// leap second table
+func RightfromPosix(posix int64) int64 {
+	return int64(labelFromUnix(posix) - TAICONST)
+}
+
+// PosixfromRight returns the POSIX Unix time of a right Unix time using the
+// leap second table. An inserted leap second has no POSIX time, it returns
+// the second before it and reports leap as true.
+func PosixfromRight(right int64) (posix int64, leap bool) {
+	return unixFromLabel(TAICONST + uint64(right))
+}
@@ -0,0 +1,66 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRightPosix(t *testing.T) {
+	// right/UTC puts the leap second of 2016-12-31 at 1483228826 and
+	// 2017-01-01T00:00:00Z at 1483228827
+	midnight := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	tests := []struct {
+		posix int64
+		right int64
+		leap  bool
+	}{
+		{0, 0, false},
+		{78796799, 78796799, false},
+		{78796800, 78796801, false},
+		{midnight - 1, 1483228825, false},
+		{midnight - 1, 1483228826, true},
+		{midnight, 1483228827, false},
+		{-86400, -86400, false},
+	}
+	for _, tt := range tests {
+		if !tt.leap {
+			if r := RightfromPosix(tt.posix); r != tt.right {
+				t.Errorf("RightfromPosix(%d) = %d, expected %d", tt.posix, r, tt.right)
+			}
+		}
+		if p, leap := PosixfromRight(tt.right); p != tt.posix || leap != tt.leap {
+			t.Errorf("PosixfromRight(%d) = %d, %v, expected %d, %v", tt.right, p, leap, tt.posix, tt.leap)
+		}
+	}
+}
+
+func TestTAIRight(t *testing.T) {
+	tai := TAIfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if r := TAIRight(tai); r != 1483228827 {
+		t.Errorf("TAIRight = %d", r)
+	}
+	if x := TAIfromRight(1483228827); x != tai {
+		t.Errorf("TAIfromRight = %v, expected %v", x, tai)
+	}
+	if x := TAIfromRight(0); x != TAIUnpack([]byte{0x40, 0, 0, 0, 0, 0, 0, 0x0a}) {
+		t.Errorf("right epoch is %v", x)
+	}
+}
+
+func TestTAINRightTime(t *testing.T) {
+	tain := TAINfromTime(time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC))
+	rt := TAINRightTime(tain)
+	if rt.Unix() != 1518636670+27 || rt.Nanosecond() != 5 {
+		t.Errorf("TAINRightTime = %v", rt)
+	}
+	if x := TAINfromRightTime(rt); x != tain {
+		t.Errorf("TAINfromRightTime = %v, expected %v", x, tain)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Smear describes a clock that spreads each leap second linearly over a
+// window instead of inserting 23:59:60. The window must contain the end
+// of the day the leap second is inserted at.
+type Smear struct {
+	// Start is where the window starts relative to the midnight after
+	// the leap second, usually negative
+	Start time.Duration
+	// Duration is the length of the window as read on the smeared clock,
+	// zero disables smearing
+	Duration time.Duration
+}
+
+var (
+	// SmearNone inserts leap seconds as 23:59:60
+	SmearNone = Smear{}
+	// SmearNoonToNoon spreads a leap second over the 24 hours from noon
+	// to noon UTC, like the major cloud providers do
+	SmearNoonToNoon = Smear{Start: -12 * time.Hour, Duration: 24 * time.Hour}
+	// Smear1000s spreads a leap second over the last 1000 seconds of the
+	// day, like UTC-SLS does
+	Smear1000s = Smear{Start: -1000 * time.Second, Duration: 1000 * time.Second}
+)
+
+// TAINfromSmearedTime returns a TAIN struct from time.Time read on a clock
+// smearing leap seconds according to s
+func TAINfromSmearedTime(t time.Time, s Smear) TAIN {
+	lt := CurrentLeapTable()
+	for i := len(lt.leaps) - 1; s.Duration > 0 && i >= 0; i-- {
+		start, leap := s.window(lt, i)
+		elapsed := t.Sub(start)
+		if elapsed < 0 || elapsed >= s.Duration {
+			continue
+		}
+		return TAINAdd(s.startLabel(lt, i, start), elapsed+mulDiv(elapsed, leap, s.Duration))
+	}
+	return TAINfromTime(t)
+}
+
+// TAINSmearedTime returns the time a clock smearing leap seconds according
+// to s reads at a TAIN timestamp
+func TAINSmearedTime(t TAIN, s Smear) time.Time {
+	lt := CurrentLeapTable()
+	for i := len(lt.leaps) - 1; s.Duration > 0 && i >= 0; i-- {
+		start, leap := s.window(lt, i)
+		elapsed := time.Duration(int64(tainNanos(t) - tainNanos(s.startLabel(lt, i, start))))
+		if elapsed < 0 || elapsed >= s.Duration+leap {
+			continue
+		}
+		return start.Add(elapsed - mulDiv(elapsed, leap, s.Duration+leap))
+	}
+	return TAINTime(t)
+}
+
+// TAIfromSmearedTime returns a TAI struct from time.Time read on a clock
+// smearing leap seconds according to s
+func TAIfromSmearedTime(t time.Time, s Smear) TAI {
+	return TAIfromTAIN(TAINfromSmearedTime(t, s))
+}
+
+// TAISmearedTime returns the time a clock smearing leap seconds according
+// to s reads at a TAI timestamp
+func TAISmearedTime(t TAI, s Smear) time.Time {
+	return TAINSmearedTime(TAIN{sec: t.x}, s)
+}
+
+// window returns the start of the smear window of lt.leaps[i] and the
+// length of the leap, negative for a removed second
+func (s Smear) window(lt *LeapTable, i int) (time.Time, time.Duration) {
+	ls := lt.leaps[i]
+	leap := time.Duration(int64(ls.offset)-lt.prevOffset(i)) * time.Second
+	return ls.begin.Add(s.Start), leap
+}
+
+// startLabel returns the TAIN label of the start of the smear window of
+// lt.leaps[i], which is still on the offset before the leap
+func (s Smear) startLabel(lt *LeapTable, i int, start time.Time) TAIN {
+	return TAIN{
+		sec:  tai64Epoch + uint64(start.Unix()+lt.prevOffset(i)),
+		nano: uint32(start.Nanosecond()),
+	}
+}
+
+// mulDiv returns elapsed * leap / window without overflowing
+func mulDiv(elapsed, leap, window time.Duration) time.Duration {
+	neg := leap < 0
+	if neg {
+		leap = -leap
+	}
+	hi, lo := bits.Mul64(uint64(elapsed), uint64(leap))
+	q, _ := bits.Div64(hi, lo, uint64(window))
+	if neg {
+		return -time.Duration(q)
+	}
+	return time.Duration(q)
+}
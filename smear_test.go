@@ -0,0 +1,77 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTAINfromSmearedTime(t *testing.T) {
+	noon := time.Date(2016, time.December, 31, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		tm       time.Time
+		smear    Smear
+		expected TAIN
+	}{
+		{"none", midnight, SmearNone, TAINfromTime(midnight)},
+		{"noon to noon start", noon, SmearNoonToNoon, TAINfromTime(noon)},
+		{"noon to noon middle", midnight, SmearNoonToNoon,
+			TAINAdd(TAINfromTime(noon), 12*time.Hour+500*time.Millisecond)},
+		{"noon to noon end", midnight.Add(12 * time.Hour), SmearNoonToNoon,
+			TAINfromTime(midnight.Add(12 * time.Hour))},
+		{"1000s middle", midnight.Add(-500 * time.Second), Smear1000s,
+			TAINAdd(TAINfromTime(midnight.Add(-1000*time.Second)), 500*time.Second+500*time.Millisecond)},
+		{"1000s end", midnight, Smear1000s, TAINfromTime(midnight)},
+		{"outside any window", noon.Add(-time.Second), SmearNoonToNoon, TAINfromTime(noon.Add(-time.Second))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := TAINfromSmearedTime(tc.tm, tc.smear); result != tc.expected {
+				t.Errorf("TAINfromSmearedTime(%v) = %v, expected %v", tc.tm, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTAINSmearedTimeRoundTrip(t *testing.T) {
+	start := time.Date(2016, time.December, 31, 11, 0, 0, 0, time.UTC)
+	for _, s := range []Smear{SmearNoonToNoon, Smear1000s, {Start: -time.Hour, Duration: 2 * time.Hour}} {
+		prev := TAIN{}
+		for d := time.Duration(0); d < 26*time.Hour; d += 7*time.Minute + 13*time.Millisecond {
+			tm := start.Add(d)
+			tain := TAINfromSmearedTime(tm, s)
+			if back := TAINSmearedTime(tain, s); back.Sub(tm).Abs() > time.Nanosecond {
+				t.Fatalf("%+v: %v converted to %v and back to %v", s, tm, tain, back)
+			}
+			if !tainLessOrEqual(prev, tain) {
+				t.Fatalf("%+v: %v is not after %v", s, tain, prev)
+			}
+			prev = tain
+		}
+	}
+}
+
+func TestTAINSmearedTimeLeapSecond(t *testing.T) {
+	leap := TAIN{sec: labelFromUnix(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()) - 1}
+	// half of the leap second has been smeared in by midnight
+	expected := time.Date(2016, time.December, 31, 23, 59, 59, 500000000, time.UTC)
+	result := TAINSmearedTime(leap, SmearNoonToNoon)
+	if result.Sub(expected).Abs() > time.Millisecond {
+		t.Errorf("TAINSmearedTime(%v) = %v, expected about %v", leap, result, expected)
+	}
+	if tai := TAISmearedTime(TAIfromTAIN(leap), Smear1000s); !tai.Before(expected.Add(time.Second)) {
+		t.Errorf("TAISmearedTime(%v) = %v", leap, tai)
+	}
+	if TAIfromSmearedTime(result, SmearNoonToNoon) != TAIfromTAIN(leap) {
+		t.Errorf("TAIfromSmearedTime(%v) = %v", result, TAIfromSmearedTime(result, SmearNoonToNoon))
+	}
+}
@@ -21,7 +21,7 @@ type TAI struct {
 
 // TAINow returns the current timestamp in TAI struct
 func TAINow() TAI {
-	return TAI{x: TAICONST + lsoffset(time.Now()) + uint64(time.Now().Unix())}
+	return TAI{x: labelFromUnix(time.Now().Unix())}
 }
 
 // TAIAdd adds a time.Duration to a TAI timestamp
@@ -42,8 +42,8 @@ func TAISub(a, b TAI) (time.Duration, error) {
 
 // TAITime returns a go time object from a TAI timestamp
 func TAITime(t TAI) time.Time {
-	tm := time.Unix(int64(t.x-TAICONST), 0).UTC()
-	return tm.Add(-time.Duration(lsoffset(tm)) * time.Second)
+	unix, _ := unixFromLabel(t.x)
+	return time.Unix(unix, 0).UTC()
 }
 
 // TAIPack packs a TAI timestamp into a byte array of size TAILength
@@ -59,11 +59,8 @@ func TAIUnpack(s []byte) TAI {
 }
 
 func (t TAI) String() string {
-	buf := TAIPack(t)
-	s := fmt.Sprintf("@%02X%02X%02X%02X%02X%02X%02X%02X",
-		buf[0], buf[1], buf[2], buf[3], buf[4], buf[5], buf[6],
-		buf[7])
-	return s
+	buf := t.Label()
+	return string(buf[:])
 }
 
 // TAIfromString returns a TAI struct from an ASCII TAI representation
@@ -82,5 +79,5 @@ func TAIfromString(str string) (TAI, error) {
 
 // TAIfromTime returns a TAI struct from time.Time
 func TAIfromTime(t time.Time) TAI {
-	return TAI{x: TAICONST + lsoffset(t) + uint64(t.Unix())}
+	return TAI{x: labelFromUnix(t.Unix())}
 }
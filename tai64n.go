@@ -24,7 +24,7 @@ type TAIN struct {
 func TAINNow() TAIN {
 	now := time.Now()
 	return TAIN{
-		sec:  TAICONST + lsoffset(now) + uint64(now.Unix()),
+		sec:  labelFromUnix(now.Unix()),
 		nano: uint32(now.Nanosecond()),
 	}
 }
@@ -77,8 +77,8 @@ func TAINSub(a, b TAIN) (time.Duration, error) {
 
 // TAINTime returns a go time object from a TAIN timestamp
 func TAINTime(t TAIN) time.Time {
-	tm := time.Unix(int64(t.sec-TAICONST), int64(t.nano)).UTC()
-	return tm.Add(-time.Duration(lsoffset(tm)) * time.Second)
+	unix, _ := unixFromLabel(t.sec)
+	return time.Unix(unix, int64(t.nano)).UTC()
 }
 
 // TAINPack packs a TAIN timestamp in a byte array of size TAINLength
@@ -98,11 +98,8 @@ func TAINUnpack(s []byte) TAIN {
 }
 
 func (t TAIN) String() string {
-	buf := TAINPack(t)
-	s := fmt.Sprintf("@%02X%02X%02X%02X%02X%02X%02X%02X%02X%02X%02X%02X",
-		buf[0], buf[1], buf[2], buf[3], buf[4], buf[5], buf[6],
-		buf[7], buf[8], buf[9], buf[10], buf[11])
-	return s
+	buf := t.Label()
+	return string(buf[:])
 }
 
 // TAINfromString returns a TAIN struct from an ASCII TAIN representation
@@ -122,6 +119,31 @@ func TAINfromString(str string) (TAIN, error) {
 // TAINfromTime returns a TAIN struct from time.Time
 func TAINfromTime(t time.Time) TAIN {
 	return TAIN{
-		sec:  TAICONST + lsoffset(t) + uint64(t.Unix()),
+		sec:  labelFromUnix(t.Unix()),
 		nano: uint32(t.Nanosecond())}
 }
+
+// TAIfromTAIN returns a TAI struct from a TAIN struct, dropping the
+// nanoseconds
+func TAIfromTAIN(t TAIN) TAI {
+	return TAI{x: t.sec}
+}
+
+// tainCompare returns -1, 0 or +1 depending on whether a is before, equal
+// to or after b
+func tainCompare(a, b TAIN) int {
+	switch {
+	case a.sec < b.sec, a.sec == b.sec && a.nano < b.nano:
+		return -1
+	case a == b:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// tainNormalize carries whole seconds out of the nanoseconds of t, which
+// TAINUnpack and TAINfromString accept up to 2^32-1
+func tainNormalize(t TAIN) TAIN {
+	return TAIN{sec: t.sec + uint64(t.nano/1e9), nano: t.nano % 1e9}
+}
@@ -201,3 +201,29 @@ func TestTAINAddEdgeCases(t *testing.T) {
 
 	runTAINTests(t, tests)
 }
+
+func TestTAINCompare(t *testing.T) {
+	a := TAIN{sec: 10, nano: 5}
+	tests := []struct {
+		b        TAIN
+		expected int
+	}{
+		{a, 0},
+		{TAIN{sec: 10, nano: 6}, -1},
+		{TAIN{sec: 10, nano: 4}, 1},
+		{TAIN{sec: 9, nano: 999999999}, 1},
+		{TAIN{sec: 11}, -1},
+	}
+	for _, tc := range tests {
+		if c := tainCompare(a, tc.b); c != tc.expected {
+			t.Errorf("tainCompare(%v, %v) = %d, expected %d", a, tc.b, c, tc.expected)
+		}
+		if le := tainLessOrEqual(a, tc.b); le != (tc.expected <= 0) {
+			t.Errorf("tainLessOrEqual(%v, %v) = %v", a, tc.b, le)
+		}
+	}
+}
+
+func tainLessOrEqual(a, b TAIN) bool {
+	return tainCompare(a, b) <= 0
+}
@@ -0,0 +1,228 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package taitest provides utilities for testing code built on glibtai.
+package taitest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/karasz/glibtai"
+)
+
+// Clock is a glibtai.Clock that only moves when told to. Timers and
+// tickers created from it fire while Advance moves the clock past their
+// deadlines. It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	start   glibtai.TAIN
+	elapsed time.Duration
+	waiters []*waiter
+	// fired, if set, is called with c.mu held for every waiter fire
+	// delivers, tests use it to see the firing order
+	fired func(*waiter)
+}
+
+// waiter is a pending timer or ticker, deadlines are kept as durations
+// since the start of the clock
+type waiter struct {
+	c        chan glibtai.TAIN
+	deadline time.Duration
+	period   time.Duration
+	fn       func()
+	active   bool
+}
+
+var _ glibtai.Clock = (*Clock)(nil)
+
+// NewClock returns a Clock reading start
+func NewClock(start glibtai.TAIN) *Clock {
+	return &Clock{start: start}
+}
+
+// TAINow returns the current fake timestamp in TAI struct
+func (c *Clock) TAINow() glibtai.TAI {
+	return glibtai.TAIfromTAIN(c.TAINNow())
+}
+
+// TAINNow returns the current fake timestamp in TAIN struct
+func (c *Clock) TAINNow() glibtai.TAIN {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return glibtai.TAINAdd(c.start, c.elapsed)
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker
+// whose deadline is reached in deadline order. Timers see the clock at
+// their deadline when they fire. Negative durations are ignored.
+func (c *Clock) Advance(d time.Duration) {
+	if d < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	target := c.elapsed + d
+	for {
+		w := c.next(target)
+		if w == nil {
+			break
+		}
+		c.elapsed = w.deadline
+		c.fire(w)
+	}
+	c.elapsed = target
+	c.mu.Unlock()
+}
+
+// next returns the active waiter with the earliest deadline not after
+// target, or nil
+func (c *Clock) next(target time.Duration) *waiter {
+	var first *waiter
+	for _, w := range c.waiters {
+		if w.active && w.deadline <= target && (first == nil || w.deadline < first.deadline) {
+			first = w
+		}
+	}
+	return first
+}
+
+// fire delivers w and reschedules or retires it
+func (c *Clock) fire(w *waiter) {
+	now := glibtai.TAINAdd(c.start, c.elapsed)
+	if c.fired != nil {
+		c.fired(w)
+	}
+	if w.period > 0 {
+		w.deadline += w.period
+	} else {
+		c.remove(w)
+	}
+
+	if w.fn != nil {
+		go w.fn()
+		return
+	}
+	select {
+	case w.c <- now:
+	default:
+		// like time.Ticker, drop ticks the receiver is too slow for
+	}
+}
+
+// add registers w
+func (c *Clock) add(w *waiter) {
+	w.active = true
+	c.waiters = append(c.waiters, w)
+}
+
+// remove unregisters w and reports whether it was active
+func (c *Clock) remove(w *waiter) bool {
+	for i, x := range c.waiters {
+		if x == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			w.active = false
+			return true
+		}
+	}
+	return false
+}
+
+// Timer is a single event of a Clock, like time.Timer
+type Timer struct {
+	// C receives the fake time when the timer fires
+	C <-chan glibtai.TAIN
+
+	clock *Clock
+	w     *waiter
+}
+
+// NewTimer returns a Timer that fires once the clock has advanced by d
+func (c *Clock) NewTimer(d time.Duration) *Timer {
+	ch := make(chan glibtai.TAIN, 1)
+	t := &Timer{C: ch, clock: c, w: &waiter{c: ch}}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc calls f in its own goroutine once the clock has advanced by
+// d, the returned Timer can be used to cancel the call
+func (c *Clock) AfterFunc(d time.Duration, f func()) *Timer {
+	t := &Timer{clock: c, w: &waiter{fn: f}}
+	t.Reset(d)
+	return t
+}
+
+// After waits for the clock to advance by d and then sends the fake time
+// on the returned channel
+func (c *Clock) After(d time.Duration) <-chan glibtai.TAIN {
+	return c.NewTimer(d).C
+}
+
+// Stop prevents the Timer from firing, it reports whether the timer was
+// still pending
+func (t *Timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t.w)
+}
+
+// Reset changes the timer to fire once the clock has advanced by d from
+// now, it reports whether the timer was still pending
+func (t *Timer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.remove(t.w)
+	t.w.deadline = c.elapsed + d
+	c.add(t.w)
+	if d <= 0 {
+		c.fire(t.w)
+	}
+	return active
+}
+
+// Ticker delivers ticks of a Clock at intervals, like time.Ticker
+type Ticker struct {
+	// C receives the fake time of every tick
+	C <-chan glibtai.TAIN
+
+	clock *Clock
+	w     *waiter
+}
+
+// NewTicker returns a Ticker that ticks every time the clock has advanced
+// by d. It panics if d is not positive.
+func (c *Clock) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("taitest: non-positive interval for NewTicker")
+	}
+	ch := make(chan glibtai.TAIN, 1)
+	t := &Ticker{C: ch, clock: c, w: &waiter{c: ch}}
+	t.Reset(d)
+	return t
+}
+
+// Stop turns off the ticker, no more ticks will be sent
+func (t *Ticker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.remove(t.w)
+}
+
+// Reset stops the ticker and restarts it with period d from now. It
+// panics if d is not positive.
+func (t *Ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("taitest: non-positive interval for Ticker.Reset")
+	}
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(t.w)
+	t.w.period = d
+	t.w.deadline = c.elapsed + d
+	c.add(t.w)
+}
@@ -0,0 +1,141 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package taitest provides utilities for testing code built on glibtai.
+package taitest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/karasz/glibtai"
+)
+
+var epoch = glibtai.TAINfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+func TestClockAdvance(t *testing.T) {
+	c := NewClock(epoch)
+	if now := c.TAINNow(); now != epoch {
+		t.Errorf("TAINNow() = %v, expected %v", now, epoch)
+	}
+	c.Advance(1500 * time.Millisecond)
+	if now, expected := c.TAINNow(), glibtai.TAINAdd(epoch, 1500*time.Millisecond); now != expected {
+		t.Errorf("TAINNow() = %v, expected %v", now, expected)
+	}
+	if now, expected := c.TAINow(), glibtai.TAIAdd(glibtai.TAIfromTAIN(epoch), time.Second); now != expected {
+		t.Errorf("TAINow() = %v, expected %v", now, expected)
+	}
+	c.Advance(-time.Hour)
+	if now, expected := c.TAINNow(), glibtai.TAINAdd(epoch, 1500*time.Millisecond); now != expected {
+		t.Errorf("TAINNow() after negative Advance = %v, expected %v", now, expected)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	c := NewClock(epoch)
+	timer := c.NewTimer(time.Minute)
+	c.Advance(59 * time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	c.Advance(2 * time.Second)
+	select {
+	case at := <-timer.C:
+		if expected := glibtai.TAINAdd(epoch, time.Minute); at != expected {
+			t.Errorf("timer fired at %v, expected %v", at, expected)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if timer.Stop() {
+		t.Error("Stop() of a fired timer reported it pending")
+	}
+	if timer.Reset(time.Second) {
+		t.Error("Reset() of a fired timer reported it pending")
+	}
+	if !timer.Stop() {
+		t.Error("Stop() of a reset timer reported it not pending")
+	}
+	c.Advance(time.Hour)
+	select {
+	case <-timer.C:
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestAfterAndAfterFunc(t *testing.T) {
+	c := NewClock(epoch)
+	ch := c.After(time.Second)
+	done := make(chan struct{})
+	c.AfterFunc(2*time.Second, func() { close(done) })
+
+	c.Advance(3 * time.Second)
+	if at := <-ch; at != glibtai.TAINAdd(epoch, time.Second) {
+		t.Errorf("After fired at %v", at)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("AfterFunc did not run")
+	}
+}
+
+func TestTicker(t *testing.T) {
+	c := NewClock(epoch)
+	ticker := c.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(10 * time.Second)
+		at := <-ticker.C
+		if expected := glibtai.TAINAdd(epoch, time.Duration(i)*10*time.Second); at != expected {
+			t.Errorf("tick %d at %v, expected %v", i, at, expected)
+		}
+	}
+
+	// ticks the receiver misses are dropped
+	c.Advance(time.Minute)
+	if at := <-ticker.C; at != glibtai.TAINAdd(epoch, 40*time.Second) {
+		t.Errorf("first pending tick at %v", at)
+	}
+	select {
+	case at := <-ticker.C:
+		t.Errorf("unexpected tick at %v", at)
+	default:
+	}
+
+	ticker.Reset(time.Second)
+	c.Advance(time.Second)
+	if at := <-ticker.C; at != glibtai.TAINAdd(epoch, 91*time.Second) {
+		t.Errorf("tick after Reset at %v", at)
+	}
+}
+
+func TestTimersFireInOrder(t *testing.T) {
+	c := NewClock(epoch)
+	var order []time.Duration
+	c.fired = func(w *waiter) { order = append(order, w.deadline) }
+	late := c.NewTimer(3 * time.Second)
+	early := c.NewTimer(time.Second)
+	c.AfterFunc(2*time.Second, func() {})
+	c.Advance(5 * time.Second)
+
+	if !slices.Equal(order, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}) {
+		t.Errorf("timers due after %v fired in the wrong order", order)
+	}
+	for _, tc := range []struct {
+		timer *Timer
+		after time.Duration
+	}{{early, time.Second}, {late, 3 * time.Second}} {
+		if at, expected := <-tc.timer.C, glibtai.TAINAdd(epoch, tc.after); at != expected {
+			t.Errorf("timer due after %v fired at %v, expected %v", tc.after, at, expected)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrTimestampingUnsupported is returned when socket timestamping is
+	// not available on this platform
+	ErrTimestampingUnsupported = errors.New("socket timestamping is not supported")
+	// ErrNoTimestamps is returned when control messages carry no socket
+	// timestamps
+	ErrNoTimestamps = errors.New("no socket timestamps in control messages")
+)
+
+// TimestampFlags are the SOF_TIMESTAMPING flags of SO_TIMESTAMPING
+type TimestampFlags uint32
+
+const (
+	// TimestampTxHardware requests hardware transmit timestamps
+	TimestampTxHardware TimestampFlags = 1 << iota
+	// TimestampTxSoftware requests software transmit timestamps
+	TimestampTxSoftware
+	// TimestampRxHardware requests hardware receive timestamps
+	TimestampRxHardware
+	// TimestampRxSoftware requests software receive timestamps
+	TimestampRxSoftware
+	// TimestampSoftware reports software timestamps
+	TimestampSoftware
+	// timestampSysHardware is deprecated by the kernel
+	timestampSysHardware
+	// TimestampRawHardware reports hardware timestamps
+	TimestampRawHardware
+)
+
+// TimestampClock is the clock a socket timestamp was read from
+type TimestampClock int
+
+const (
+	// TimestampRealtime is CLOCK_REALTIME or a PTP hardware clock kept
+	// in UTC
+	TimestampRealtime TimestampClock = iota
+	// TimestampTAI is CLOCK_TAI or a PTP hardware clock kept in TAI, as
+	// ptp4l does
+	TimestampTAI
+)
+
+func (c TimestampClock) String() string {
+	switch c {
+	case TimestampRealtime:
+		return "realtime"
+	case TimestampTAI:
+		return "TAI"
+	default:
+		return "TimestampClock(" + strconv.Itoa(int(c)) + ")"
+	}
+}
+
+// SocketTimestamps are the timestamps of one SO_TIMESTAMPING control
+// message. Timestamps the kernel did not fill in are zero and reported as
+// missing.
+type SocketTimestamps struct {
+	Software    TAIN
+	HasSoftware bool
+	Hardware    TAIN
+	HasHardware bool
+}
+
+// tainFromTimespec returns the TAIN timestamp of a timespec read from clock
+func tainFromTimespec(sec, nsec int64, clock TimestampClock) TAIN {
+	if clock == TimestampTAI {
+		return TAIN{sec: tai64Epoch + uint64(sec), nano: uint32(nsec)}
+	}
+	return TAIN{sec: labelFromUnix(sec), nano: uint32(nsec)}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+//go:build linux
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// EnableTimestamping sets SO_TIMESTAMPING with flags on the socket of conn,
+// such as a *net.UDPConn. Hardware timestamps also need the interface to
+// be configured with SIOCSHWTSTAMP.
+func EnableTimestamping(conn syscall.Conn, flags TimestampFlags) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	err = rc.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_TIMESTAMPING, int(flags))
+	})
+	if err == nil {
+		err = serr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTimestampingUnsupported, err)
+	}
+	return nil
+}
+
+// ParseTimestamping returns the timestamps of the SCM_TIMESTAMPING control
+// message in oob, the out of band data of ReadMsgUDP. Software timestamps
+// are CLOCK_REALTIME, hardware timestamps are read from hw. It returns
+// ErrNoTimestamps when there is no such message.
+func ParseTimestamping(oob []byte, hw TimestampClock) (SocketTimestamps, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return SocketTimestamps{}, err
+	}
+	for _, m := range msgs {
+		if m.Header.Level == syscall.SOL_SOCKET && m.Header.Type == syscall.SO_TIMESTAMPING {
+			return parseScmTimestamping(m.Data, hw)
+		}
+	}
+	return SocketTimestamps{}, ErrNoTimestamps
+}
+
+// parseScmTimestamping decodes a struct scm_timestamping: a software
+// timestamp, a deprecated one and a raw hardware timestamp
+func parseScmTimestamping(data []byte, hw TimestampClock) (SocketTimestamps, error) {
+	var ts [3]syscall.Timespec
+	if len(data) < int(unsafe.Sizeof(ts)) {
+		return SocketTimestamps{}, fmt.Errorf("scm_timestamping of %d bytes is too short", len(data))
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&ts)), unsafe.Sizeof(ts)), data)
+
+	var st SocketTimestamps
+	if sw := ts[0]; sw.Sec != 0 || sw.Nsec != 0 {
+		st.Software = tainFromTimespec(int64(sw.Sec), int64(sw.Nsec), TimestampRealtime)
+		st.HasSoftware = true
+	}
+	if raw := ts[2]; raw.Sec != 0 || raw.Nsec != 0 {
+		st.Hardware = tainFromTimespec(int64(raw.Sec), int64(raw.Nsec), hw)
+		st.HasHardware = true
+	}
+	return st, nil
+}
@@ -0,0 +1,26 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+//go:build !linux
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import "syscall"
+
+// EnableTimestamping sets SO_TIMESTAMPING on the socket of conn.
+// SO_TIMESTAMPING is only available on Linux, so it always returns
+// ErrTimestampingUnsupported.
+func EnableTimestamping(_ syscall.Conn, _ TimestampFlags) error {
+	return ErrTimestampingUnsupported
+}
+
+// ParseTimestamping returns the timestamps of an SCM_TIMESTAMPING control
+// message. SO_TIMESTAMPING is only available on Linux, so it always returns
+// ErrTimestampingUnsupported.
+func ParseTimestamping(_ []byte, _ TimestampClock) (SocketTimestamps, error) {
+	return SocketTimestamps{}, ErrTimestampingUnsupported
+}
@@ -0,0 +1,83 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSoftwareTimestampingLoopback(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer conn.Close()
+
+	err = EnableTimestamping(conn, TimestampRxSoftware|TimestampSoftware)
+	if errors.Is(err, ErrTimestampingUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	before := TAINNow()
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf, oob := make([]byte, 16), make([]byte, 256)
+	_, oobn, _, _, err := conn.ReadMsgUDP(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := TAINNow()
+
+	ts, err := ParseTimestamping(oob[:oobn], TimestampTAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ts.HasSoftware || ts.HasHardware {
+		t.Fatalf("timestamps are %+v", ts)
+	}
+	if d, _ := TAINSub(ts.Software, before); d < -time.Millisecond {
+		t.Errorf("software timestamp %v is before the send at %v", ts.Software, before)
+	}
+	if d, _ := TAINSub(after, ts.Software); d < -time.Millisecond {
+		t.Errorf("software timestamp %v is after the receive at %v", ts.Software, after)
+	}
+}
+
+func TestParseTimestampingWithoutTimestamps(t *testing.T) {
+	_, err := ParseTimestamping(nil, TimestampRealtime)
+	if !errors.Is(err, ErrNoTimestamps) && !errors.Is(err, ErrTimestampingUnsupported) {
+		t.Errorf("ParseTimestamping(nil) returned %v", err)
+	}
+}
+
+func TestTAINfromTimespec(t *testing.T) {
+	tm := time.Date(2018, time.February, 14, 19, 31, 10, 5, time.UTC)
+	if x := tainFromTimespec(tm.Unix(), 5, TimestampRealtime); x != TAINfromTime(tm) {
+		t.Errorf("realtime timespec is %v", x)
+	}
+	if x := tainFromTimespec(tm.Unix()+37, 5, TimestampTAI); x != TAINfromTime(tm) {
+		t.Errorf("TAI timespec is %v", x)
+	}
+	if s := TimestampTAI.String(); s != "TAI" {
+		t.Errorf("String() = %s", s)
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadTZLeapSeconds reads a leap second table from a tz database
+// leapseconds file such as /usr/share/zoneinfo/leapseconds, see
+// ParseTZLeapSeconds
+func ReadTZLeapSeconds(name string) (*LeapTable, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseTZLeapSeconds(f)
+}
+
+// ParseTZLeapSeconds parses the tz database leapseconds format, whose
+// lines read "Leap YEAR MONTH DAY HH:MM:SS CORR R/S". The expiry is taken
+// from an "Expires YEAR MONTH DAY HH:MM:SS" line or, as recent files have
+// it commented out, from a "#expires UNIXTIME" comment. Other lines and
+// comments are ignored.
+func ParseTZLeapSeconds(r io.Reader) (*LeapTable, error) {
+	var leaps []LeapSecond
+	var expires time.Time
+	offset := baseOffset
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line, comment, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(line)
+		var err error
+		switch {
+		case len(fields) == 0:
+			expires, err = parseTZExpiresComment(comment, expires)
+		case fields[0] == "Leap":
+			var begin time.Time
+			var corr int
+			begin, corr, err = parseTZLeapLine(fields)
+			offset += corr
+			leaps = append(leaps, LeapSecond{Begin: begin, Offset: offset})
+		case fields[0] == "Expires":
+			expires, err = time.Parse("2006 Jan 2 15:04:05", strings.Join(fields[1:], " "))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("leapseconds line %d: %w", n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	t, err := NewLeapTable(leaps)
+	if err != nil {
+		return nil, err
+	}
+	return t.WithValidUntil(expires), nil
+}
+
+// parseTZExpiresComment returns the time of an "expires UNIXTIME" comment,
+// or expires for any other comment
+func parseTZExpiresComment(comment string, expires time.Time) (time.Time, error) {
+	fields := strings.Fields(comment)
+	if len(fields) < 2 || fields[0] != "expires" {
+		return expires, nil
+	}
+	unix, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return expires, err
+	}
+	return time.Unix(unix, 0).UTC(), nil
+}
+
+// parseTZLeapLine parses the fields of a Leap line and returns the UTC
+// midnight after the leap second and its correction
+func parseTZLeapLine(fields []string) (time.Time, int, error) {
+	if len(fields) != 7 {
+		return time.Time{}, 0, fmt.Errorf("Leap line has %d fields, expected 7", len(fields))
+	}
+	day, err := time.Parse("2006 Jan 2", strings.Join(fields[1:4], " "))
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	var corr int
+	switch {
+	case fields[5] == "+" && fields[4] == "23:59:60":
+		corr = 1
+	case fields[5] == "-" && fields[4] == "23:59:59":
+		corr = -1
+	default:
+		return time.Time{}, 0, fmt.Errorf("leap second %s %s is not valid", fields[4], fields[5])
+	}
+	if fields[6] != "S" && fields[6] != "R" {
+		return time.Time{}, 0, fmt.Errorf("leap second type %q is not valid", fields[6])
+	}
+	return day.AddDate(0, 0, 1), corr, nil
+}
+
+// ReadTZif reads the leap second records of a TZif file, such as
+// /usr/share/zoneinfo/right/UTC, see ParseTZif
+func ReadTZif(name string) (*LeapTable, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTZif(data)
+}
+
+// tzifHeader holds the counts of a TZif header
+type tzifHeader struct {
+	version                                               byte
+	isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt int
+}
+
+// ParseTZif returns the leap second records of TZif data as a LeapTable.
+// Version 1 files use their 32 bit records, later versions the 64 bit
+// ones. Files without leap records return an empty table.
+func ParseTZif(data []byte) (*LeapTable, error) {
+	h, err := parseTZifHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	size := 4
+	if h.version != 0 {
+		if len(data) < 44+h.blockLen(4) {
+			return nil, errTZifTruncated
+		}
+		data = data[44+h.blockLen(4):]
+		if h, err = parseTZifHeader(data); err != nil {
+			return nil, err
+		}
+		size = 8
+	}
+
+	body := data[44:]
+	if len(body) < h.blockLen(size) {
+		return nil, errTZifTruncated
+	}
+	leaps := body[h.timecnt*(size+1)+h.typecnt*6+h.charcnt:]
+	return tzifLeaps(leaps[:h.leapcnt*(size+4)], size)
+}
+
+// errTZifTruncated is returned for TZif data shorter than its header says
+var errTZifTruncated = errors.New("TZif data is truncated")
+
+// parseTZifHeader parses the 44 byte header at the start of data
+func parseTZifHeader(data []byte) (tzifHeader, error) {
+	if len(data) < 44 || !bytes.HasPrefix(data, []byte("TZif")) {
+		return tzifHeader{}, errors.New("not a TZif file")
+	}
+	h := tzifHeader{version: data[4]}
+	if h.version != 0 && (h.version < '2' || h.version > '9') {
+		return tzifHeader{}, fmt.Errorf("TZif version %q is not supported", h.version)
+	}
+	counts := []*int{&h.isutcnt, &h.isstdcnt, &h.leapcnt, &h.timecnt, &h.typecnt, &h.charcnt}
+	for i, c := range counts {
+		n := binary.BigEndian.Uint32(data[20+4*i:])
+		if n > 1<<20 {
+			return tzifHeader{}, fmt.Errorf("TZif count %d is too large", n)
+		}
+		*c = int(n)
+	}
+	return h, nil
+}
+
+// blockLen returns the length of the data block after the header, with
+// times of size bytes
+func (h tzifHeader) blockLen(size int) int {
+	return h.timecnt*(size+1) + h.typecnt*6 + h.charcnt + h.leapcnt*(size+4) + h.isstdcnt + h.isutcnt
+}
+
+// tzifLeaps converts leap records into a LeapTable. A record holds the
+// time_t of the second after the leap, which counts the leap seconds
+// before it, and the total correction from then on. A last record
+// repeating the correction before it only marks the expiry of the table.
+func tzifLeaps(data []byte, size int) (*LeapTable, error) {
+	var leaps []LeapSecond
+	var expires time.Time
+	prev := 0
+	for len(data) > 0 {
+		var occurrence int64
+		if size == 8 {
+			occurrence = int64(binary.BigEndian.Uint64(data))
+		} else {
+			occurrence = int64(int32(binary.BigEndian.Uint32(data)))
+		}
+		corr := int(int32(binary.BigEndian.Uint32(data[size:])))
+		data = data[size+4:]
+
+		if corr == prev && len(data) == 0 {
+			expires = time.Unix(occurrence-int64(corr), 0).UTC()
+			break
+		}
+		begin := occurrence - int64(prev)
+		if corr < prev {
+			// the deleted second is counted in the correction already
+			begin = occurrence - int64(corr)
+		}
+		leaps = append(leaps, LeapSecond{Begin: time.Unix(begin, 0), Offset: baseOffset + corr})
+		prev = corr
+	}
+
+	t, err := NewLeapTable(leaps)
+	if err != nil {
+		return nil, err
+	}
+	return t.WithValidUntil(expires), nil
+}
+
+// String returns the table in the tz database leapseconds format, with an
+// Expires line if the table expires
+func (t *LeapTable) String() string {
+	var b strings.Builder
+	prev := baseOffset
+	for _, ls := range t.leaps {
+		day := ls.begin.AddDate(0, 0, -1)
+		clock, corr := "23:59:60", "+"
+		if ls.offset < prev {
+			clock, corr = "23:59:59", "-"
+		}
+		b.WriteString("Leap\t" + strconv.Itoa(day.Year()) + "\t" + day.Format("Jan\t2") + "\t" + clock + "\t" + corr + "\tS\n")
+		prev = ls.offset
+	}
+	if !t.validUntil.IsZero() {
+		b.WriteString("Expires\t" + t.validUntil.Format("2006\tJan\t2\t15:04:05") + "\n")
+	}
+	return b.String()
+}
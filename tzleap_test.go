@@ -0,0 +1,215 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tzLeapSecondsSample is the start of a tz leapseconds file
+const tzLeapSecondsSample = `# Allowance for leap seconds added to each time zone from 1970.
+#Expires 2026	Jun	28	00:00:00
+
+Leap	1972	Jun	30	23:59:60	+	S
+Leap	1972	Dec	31	23:59:60	+	S # a comment
+Leap	1973	Dec	31	23:59:60	+	S
+`
+
+// tzifLeapData returns TZif data of the given version with 64 bit leap
+// records, and 32 bit ones in the version 1 block
+func tzifLeapData(version byte, records [][2]int64) []byte {
+	header := func(size int) []byte {
+		h := make([]byte, 44)
+		copy(h, "TZif")
+		h[4] = version
+		binary.BigEndian.PutUint32(h[28:], uint32(len(records)))
+		binary.BigEndian.PutUint32(h[36:], 1)
+		binary.BigEndian.PutUint32(h[40:], 4)
+		body := append(h, 0, 0, 0, 0, 0, 0)
+		body = append(body, "UTC\x00"...)
+		for _, r := range records {
+			if size == 8 {
+				body = binary.BigEndian.AppendUint64(body, uint64(r[0]))
+			} else {
+				body = binary.BigEndian.AppendUint32(body, uint32(r[0]))
+			}
+			body = binary.BigEndian.AppendUint32(body, uint32(r[1]))
+		}
+		return body
+	}
+	data := header(4)
+	if version != 0 {
+		data = append(data, header(8)...)
+		data = append(data, "\nUTC0\n"...)
+	}
+	return data
+}
+
+func TestParseTZLeapSeconds(t *testing.T) {
+	table, err := ParseTZLeapSeconds(strings.NewReader(tzLeapSecondsSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 {
+		t.Fatalf("parsed %d leap seconds, expected 3", table.Len())
+	}
+	for tm, offset := range map[time.Time]int{
+		time.Date(1972, time.June, 30, 23, 59, 59, 0, time.UTC): 10,
+		time.Date(1972, time.July, 1, 0, 0, 0, 0, time.UTC):     11,
+		time.Date(1973, time.June, 1, 0, 0, 0, 0, time.UTC):     12,
+		time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC):     13,
+	} {
+		if o := table.Offset(tm); o != offset {
+			t.Errorf("offset at %v is %d, expected %d", tm, o, offset)
+		}
+	}
+	if !table.ValidUntil().IsZero() {
+		t.Errorf("commented Expires line was read as %v", table.ValidUntil())
+	}
+	if s := leapLines(table); s != "Leap\t1972\tJun\t30\t23:59:60\t+\tS\nLeap\t1972\tDec\t31\t23:59:60\t+\tS\nLeap\t1973\tDec\t31\t23:59:60\t+\tS\n" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestParseTZLeapSecondsExpires(t *testing.T) {
+	expires := time.Date(2026, time.June, 28, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{
+		"Expires\t2026\tJun\t28\t00:00:00\n" + tzLeapSecondsSample,
+		"#expires 1782604800 (2026-06-28 00:00:00 UTC)\n" + tzLeapSecondsSample,
+	} {
+		table, err := ParseTZLeapSeconds(strings.NewReader(s))
+		if err != nil {
+			t.Errorf("ParseTZLeapSeconds(%q): %v", s, err)
+			continue
+		}
+		if !table.ValidUntil().Equal(expires) {
+			t.Errorf("ParseTZLeapSeconds(%q) expires %v", s, table.ValidUntil())
+		}
+		if !strings.HasSuffix(table.String(), "Expires\t2026\tJun\t28\t00:00:00\n") {
+			t.Errorf("String() = %q", table.String())
+		}
+	}
+	for _, s := range []string{"Expires\t2026\tJux\t28\t00:00:00", "#expires soon"} {
+		if _, err := ParseTZLeapSeconds(strings.NewReader(s)); err == nil {
+			t.Errorf("ParseTZLeapSeconds(%q) succeeded", s)
+		}
+	}
+}
+
+func TestParseTZLeapSecondsErrors(t *testing.T) {
+	for _, s := range []string{
+		"Leap\t1972\tJun\t30\t23:59:60\t+",
+		"Leap\t1972\tJux\t30\t23:59:60\t+\tS",
+		"Leap\t1972\tJun\t30\t23:59:59\t+\tS",
+		"Leap\t1972\tJun\t30\t23:59:60\t+\tX",
+		"Leap\t1972\tDec\t31\t23:59:60\t+\tS\nLeap\t1972\tJun\t30\t23:59:60\t+\tS",
+	} {
+		if _, err := ParseTZLeapSeconds(strings.NewReader(s)); err == nil {
+			t.Errorf("ParseTZLeapSeconds(%q) succeeded", s)
+		}
+	}
+}
+
+func TestParseTZif(t *testing.T) {
+	records := [][2]int64{{78796800, 1}, {94694401, 2}, {126230402, 3}}
+	for _, version := range []byte{0, '2', '4'} {
+		table, err := ParseTZif(tzifLeapData(version, records))
+		if err != nil {
+			t.Fatalf("version %q: %v", version, err)
+		}
+		expected, _ := ParseTZLeapSeconds(strings.NewReader(tzLeapSecondsSample))
+		if table.String() != expected.String() {
+			t.Errorf("version %q parsed %q", version, table.String())
+		}
+	}
+
+	// version 4 marks the expiry with a record repeating the correction
+	expiring := append(records, [2]int64{1782604800 + 3, 3})
+	table, err := ParseTZif(tzifLeapData('4', expiring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 || table.ValidUntil().Unix() != 1782604800 {
+		t.Errorf("expiry record gave %d leap seconds until %v", table.Len(), table.ValidUntil())
+	}
+}
+
+func TestParseTZifErrors(t *testing.T) {
+	data := tzifLeapData('2', [][2]int64{{78796800, 1}})
+	bad := append([]byte{}, data...)
+	bad[4] = '1'
+	for _, d := range [][]byte{nil, []byte("TZjf"), data[:len(data)-12], bad} {
+		if _, err := ParseTZif(d); err == nil {
+			t.Errorf("ParseTZif(%q) succeeded", d)
+		}
+	}
+
+	// a v2 file cut inside its version 1 data block
+	if _, err := ParseTZif(data[:50]); err != errTZifTruncated {
+		t.Errorf("truncated v2 file: %v", err)
+	}
+}
+
+// leapLines returns the Leap lines of a table in tz leapseconds format
+func leapLines(t *LeapTable) string {
+	return t.WithValidUntil(time.Time{}).String()
+}
+
+func TestSystemLeapSecondFiles(t *testing.T) {
+	builtin := leapLines(BuiltinLeapTable())
+	for name, read := range map[string]func(string) (*LeapTable, error){
+		"/usr/share/zoneinfo/leapseconds": ReadTZLeapSeconds,
+		"/usr/share/zoneinfo/right/UTC":   ReadTZif,
+	} {
+		if _, err := os.Stat(name); err != nil {
+			t.Logf("skipping %s: %v", name, err)
+			continue
+		}
+		table, err := read(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if s := leapLines(table); !strings.HasPrefix(s, builtin) {
+			t.Errorf("%s does not match the built-in table:\n%s", name, s)
+		}
+	}
+	if _, err := ReadTZLeapSeconds("/nonexistent/leapseconds"); err == nil {
+		t.Error("ReadTZLeapSeconds of a missing file succeeded")
+	}
+	if _, err := ReadTZif("/nonexistent/UTC"); err == nil {
+		t.Error("ReadTZif of a missing file succeeded")
+	}
+}
+
+func TestNegativeLeapSecondFiles(t *testing.T) {
+	text := "Leap\t1972\tJun\t30\t23:59:60\t+\tS\nLeap\t1972\tDec\t31\t23:59:59\t-\tS\nLeap\t1973\tDec\t31\t23:59:60\t+\tS\n"
+	table, err := ParseTZLeapSeconds(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := table.String(); s != text {
+		t.Errorf("String() = %q", s)
+	}
+	if o := table.Offset(time.Date(1973, time.June, 1, 0, 0, 0, 0, time.UTC)); o != 10 {
+		t.Errorf("offset after the removed second is %d", o)
+	}
+
+	// the leap records zic writes for the same table
+	tzif, err := ParseTZif(tzifLeapData('2', [][2]int64{{78796800, 1}, {94694400, 0}, {126230400, 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := tzif.String(); s != text {
+		t.Errorf("TZif table is %q", s)
+	}
+}
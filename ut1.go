@@ -0,0 +1,230 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrEOPRange is returned when a timestamp is outside of the days covered
+// by an EOPTable
+var ErrEOPRange = errors.New("timestamp outside of the Earth orientation table")
+
+// EOPEntry is the UT1 - UTC value of one day of an IERS Bulletin A
+type EOPEntry struct {
+	// MJD is the Modified Julian Date of the day, the value applies at
+	// 0h UTC
+	MJD int
+	// DUT1 is UT1 - UTC
+	DUT1 time.Duration
+	// Predicted is true for predicted rather than observed values
+	Predicted bool
+}
+
+// EOPTable holds daily UT1 - UTC values, sorted by day. Lookups follow
+// the current leap second table, also after SetLeapTable.
+type EOPTable struct {
+	entries []EOPEntry
+	scale   atomic.Pointer[eopScale]
+}
+
+// eopScale holds the entries of an EOPTable on the TAI scale of a leap
+// second table
+type eopScale struct {
+	leaps *LeapTable
+	// labels are the TAI64 labels of 0h UTC of every entry and ut1tai
+	// the matching UT1 - TAI in seconds, which has no leap second jumps
+	labels []uint64
+	ut1tai []float64
+}
+
+// ReadFinals2000A reads an IERS finals2000A.all, finals2000A.data or
+// finals2000A.daily file, see ParseFinals2000A
+func ReadFinals2000A(name string) (*EOPTable, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseFinals2000A(f)
+}
+
+// ParseFinals2000A parses the fixed column format of the IERS finals2000A
+// files and returns their Bulletin A UT1 - UTC values. Lines without a
+// UT1 - UTC value, at the end of the predictions, are skipped.
+func ParseFinals2000A(r io.Reader) (*EOPTable, error) {
+	var entries []EOPEntry
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		e, ok, err := parseFinalsLine(sc.Text())
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: %w", n, err)
+		}
+		if !ok {
+			continue
+		}
+		if len(entries) > 0 && e.MJD <= entries[len(entries)-1].MJD {
+			return nil, fmt.Errorf("finals2000A line %d: MJD %d is out of order", n, e.MJD)
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return NewEOPTable(entries)
+}
+
+// parseFinalsLine parses one line, ok is false when it has no UT1 - UTC
+func parseFinalsLine(line string) (e EOPEntry, ok bool, err error) {
+	if len(line) < 68 || line[57] == ' ' {
+		return e, false, nil
+	}
+	mjd, err := strconv.ParseFloat(strings.TrimSpace(line[7:15]), 64)
+	if err != nil {
+		return e, false, err
+	}
+	dut1, err := strconv.ParseFloat(strings.TrimSpace(line[58:68]), 64)
+	if err != nil {
+		return e, false, err
+	}
+	if flag := line[57]; flag != 'I' && flag != 'P' {
+		return e, false, fmt.Errorf("unknown UT1 - UTC flag %q", flag)
+	}
+
+	e = EOPEntry{
+		MJD:       int(mjd),
+		DUT1:      time.Duration(math.Round(dut1 * 1e9)),
+		Predicted: line[57] == 'P',
+	}
+	return e, true, nil
+}
+
+// NewEOPTable returns an EOPTable of entries, which must be sorted by day
+func NewEOPTable(entries []EOPEntry) (*EOPTable, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("no UT1 - UTC values")
+	}
+
+	t := &EOPTable{entries: append([]EOPEntry(nil), entries...)}
+	for i, e := range t.entries {
+		if i > 0 && e.MJD <= t.entries[i-1].MJD {
+			return nil, fmt.Errorf("MJD %d is out of order", e.MJD)
+		}
+	}
+	t.onScale()
+	return t, nil
+}
+
+// onScale returns the entries of t on the scale of the current leap
+// second table, converting them again when the table was replaced
+func (t *EOPTable) onScale() *eopScale {
+	lt := CurrentLeapTable()
+	if s := t.scale.Load(); s != nil && s.leaps == lt {
+		return s
+	}
+
+	s := &eopScale{leaps: lt}
+	for _, e := range t.entries {
+		unix := int64(e.MJD-mjdUnix) * 86400
+		lt.checkExpiry(unix)
+		s.labels = append(s.labels, lt.labelFromUnix(unix))
+		s.ut1tai = append(s.ut1tai, e.DUT1.Seconds()-float64(lt.utcOffset(unix)))
+	}
+	t.scale.Store(s)
+	return s
+}
+
+// mjdUnix is the Modified Julian Date of 1970-01-01
+const mjdUnix = 40587
+
+// Entries returns a copy of the daily values of t
+func (t *EOPTable) Entries() []EOPEntry {
+	return append([]EOPEntry(nil), t.entries...)
+}
+
+// ut1MinusTAI returns UT1 - TAI at x in seconds, interpolated linearly
+// between the days around it
+func (s *eopScale) ut1MinusTAI(x TAIN) (float64, error) {
+	n := len(s.labels)
+	if x.sec < s.labels[0] || x.sec > s.labels[n-1] || x.sec == s.labels[n-1] && x.nano > 0 {
+		return 0, ErrEOPRange
+	}
+
+	i := sort.Search(n, func(i int) bool { return s.labels[i] > x.sec })
+	if i == n {
+		return s.ut1tai[n-1], nil
+	}
+	span := float64(s.labels[i] - s.labels[i-1])
+	pos := (float64(x.sec-s.labels[i-1]) + float64(x.nano)/1e9) / span
+	return s.ut1tai[i-1] + pos*(s.ut1tai[i]-s.ut1tai[i-1]), nil
+}
+
+// DUT1 returns UT1 - UTC at x, interpolated between the daily values
+func (t *EOPTable) DUT1(x TAIN) (time.Duration, error) {
+	s := t.onScale()
+	d, err := s.ut1MinusTAI(x)
+	if err != nil {
+		return 0, err
+	}
+	unix, _ := s.leaps.unixFromLabel(x.sec)
+	s.leaps.checkExpiry(unix)
+	return time.Duration(math.Round((d + float64(s.leaps.utcOffset(unix))) * 1e9)), nil
+}
+
+// UT1 returns x as a Julian Date in UT1
+func (t *EOPTable) UT1(x TAIN) (JulianDate, error) {
+	d, err := t.onScale().ut1MinusTAI(x)
+	if err != nil {
+		return JulianDate{}, err
+	}
+	return taiJulianDate(x).add(d / 86400), nil
+}
+
+// GMST returns the Greenwich mean sidereal time at x in radians
+func (t *EOPTable) GMST(x TAIN) (float64, error) {
+	ut1, err := t.UT1(x)
+	if err != nil {
+		return 0, err
+	}
+	return GMST(ut1, JulianDatefromTAIN(x, TT)), nil
+}
+
+// EarthRotationAngle returns the IAU 2000 Earth rotation angle at a UT1
+// Julian Date in radians
+func EarthRotationAngle(ut1 JulianDate) float64 {
+	days := (ut1.Day - jdJ2000) + ut1.Frac
+	f := math.Mod(ut1.Day, 1) + math.Mod(ut1.Frac, 1)
+	return normalizeAngle(2 * math.Pi * (f + 0.7790572732640 + 0.00273781191135448*days))
+}
+
+// GMST returns the IAU 2006 Greenwich mean sidereal time in radians from
+// the same instant as Julian Dates in UT1 and TT
+func GMST(ut1, tt JulianDate) float64 {
+	c := ((tt.Day - jdJ2000) + tt.Frac) / 36525
+	arcsec := 0.014506 + (4612.156534+(1.3915817+(-0.00000044+(-0.000029956+(-0.0000000368)*c)*c)*c)*c)*c
+	return normalizeAngle(EarthRotationAngle(ut1) + arcsec*math.Pi/(180*3600))
+}
+
+// normalizeAngle returns a in [0, 2π)
+func normalizeAngle(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a
+}
@@ -0,0 +1,172 @@
+// Copyright © 2018 Nagy Károly Gábriel <karasz@jpi.io>
+// This file, part of glibtai, is free and unencumbered software
+// released into the public domain.
+// For more information, please refer to <http://unlicense.org/>
+
+// Package glibtai is a partial Go implementation of libtai. See
+// http://cr.yp.to/libtai/ for more information.
+package glibtai
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// finalsLine formats a finals2000A line with polar motion and UT1 - UTC
+func finalsLine(y, m, d, mjd int, flag byte, dut1 float64) string {
+	return fmt.Sprintf("%02d%02d%02d %8.2f I %9.6f%9.6f %9.6f%9.6f  %c%10.7f%10.7f  1.4268 0.0076",
+		y%100, m, d, float64(mjd), 0.034483, 0.000023, 0.282843, 0.000026, flag, dut1, 0.0000089)
+}
+
+// finalsAroundLeap holds the days around the leap second of 2016-12-31
+var finalsAroundLeap = strings.Join([]string{
+	finalsLine(2016, 12, 30, 57752, 'I', -0.5906),
+	finalsLine(2016, 12, 31, 57753, 'I', -0.5920),
+	finalsLine(2017, 1, 1, 57754, 'I', 0.4066),
+	finalsLine(2017, 1, 2, 57755, 'P', 0.4052),
+	"17 1 3 57756.00 P  0.030000 0.000100  0.280000 0.000100",
+}, "\n")
+
+func TestParseFinals2000A(t *testing.T) {
+	table, err := ParseFinals2000A(strings.NewReader(finalsAroundLeap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := table.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("parsed %d entries, expected 4", len(entries))
+	}
+	if e := entries[2]; e.MJD != 57754 || e.DUT1 != 406600*time.Microsecond || e.Predicted {
+		t.Errorf("entry 2 is %+v", e)
+	}
+	if !entries[3].Predicted {
+		t.Errorf("entry 3 is not predicted")
+	}
+}
+
+func TestParseFinals2000AErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		finalsLine(2016, 12, 31, 57753, 'X', -0.5920),
+		finalsLine(2016, 12, 31, 57753, 'I', -0.5920) + "\n" + finalsLine(2016, 12, 30, 57752, 'I', -0.5906),
+		strings.Replace(finalsLine(2016, 12, 31, 57753, 'I', -0.5920), "57753.00", "5775x.00", 1),
+	} {
+		if _, err := ParseFinals2000A(strings.NewReader(s)); err == nil {
+			t.Errorf("ParseFinals2000A(%q) succeeded", s)
+		}
+	}
+}
+
+func TestReadFinals2000A(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "finals2000A.daily")
+	if err := os.WriteFile(name, []byte(finalsAroundLeap), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFinals2000A(name); err != nil {
+		t.Error(err)
+	}
+	if _, err := ReadFinals2000A(name + ".missing"); err == nil {
+		t.Error("ReadFinals2000A of a missing file succeeded")
+	}
+}
+
+func TestDUT1AcrossLeapSecond(t *testing.T) {
+	table, _ := ParseFinals2000A(strings.NewReader(finalsAroundLeap))
+	tests := []struct {
+		tm   time.Time
+		dut1 time.Duration
+	}{
+		{time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC), -592 * time.Millisecond},
+		// UT1 - TAI goes from -36.5920s to -36.5934s during the 86401s
+		// of the day
+		{time.Date(2016, time.December, 31, 12, 0, 0, 0, time.UTC), -592699992 * time.Nanosecond},
+		{time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC), 406600 * time.Microsecond},
+		{time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC), 405200 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		d, err := table.DUT1(TAINfromTime(tt.tm))
+		if err != nil || d != tt.dut1 {
+			t.Errorf("DUT1 at %v is %v, %v, expected %v", tt.tm, d, err, tt.dut1)
+		}
+	}
+
+	for _, tm := range []time.Time{
+		time.Date(2016, time.December, 29, 23, 59, 59, 0, time.UTC),
+		time.Date(2017, time.January, 2, 0, 0, 0, 1, time.UTC),
+	} {
+		if _, err := table.DUT1(TAINfromTime(tm)); !errors.Is(err, ErrEOPRange) {
+			t.Errorf("DUT1 at %v returned %v", tm, err)
+		}
+		if _, err := table.GMST(TAINfromTime(tm)); !errors.Is(err, ErrEOPRange) {
+			t.Errorf("GMST at %v returned %v", tm, err)
+		}
+	}
+}
+
+func TestDUT1FollowsLeapTable(t *testing.T) {
+	table, _ := ParseFinals2000A(strings.NewReader(finalsAroundLeap))
+	leaps := slices.Collect(Leaps())
+	without, err := NewLeapTable(leaps[:len(leaps)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetLeapTable(nil)
+
+	// the table was built before SetLeapTable and still has to agree
+	// with the leap second table current at lookup
+	for _, lt := range []*LeapTable{without, nil} {
+		SetLeapTable(lt)
+		for _, tc := range []struct {
+			tm   time.Time
+			dut1 time.Duration
+		}{
+			{time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC), -592 * time.Millisecond},
+			{time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC), 405200 * time.Microsecond},
+		} {
+			d, err := table.DUT1(TAINfromTime(tc.tm))
+			if err != nil || d != tc.dut1 {
+				t.Errorf("with %d leap seconds DUT1 at %v is %v, %v, expected %v",
+					CurrentLeapTable().Len(), tc.tm, d, err, tc.dut1)
+			}
+		}
+	}
+}
+
+func TestUT1(t *testing.T) {
+	table, _ := ParseFinals2000A(strings.NewReader(finalsAroundLeap))
+	tain := TAINfromTime(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	j, err := table.UT1(tain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Day != 2457754.5 || math.Abs(j.Frac*86400-0.4066) > 1e-6 {
+		t.Errorf("UT1 is %+v", j)
+	}
+}
+
+func TestEarthRotationAngleAndGMST(t *testing.T) {
+	// reference values from the SOFA iauEra00 and iauGmst06 tests
+	if era := EarthRotationAngle(JulianDate{Day: 2400000.5, Frac: 54388.0}); math.Abs(era-0.4022837240028158102) > 1e-12 {
+		t.Errorf("ERA is %v", era)
+	}
+	j := JulianDate{Day: 2453736.0, Frac: 0.5}
+	if gmst := GMST(j, j); math.Abs(gmst-1.754174971870091203) > 1e-12 {
+		t.Errorf("GMST is %v", gmst)
+	}
+}
+
+func TestNewEOPTableErrors(t *testing.T) {
+	if _, err := NewEOPTable(nil); err == nil {
+		t.Error("empty table accepted")
+	}
+	if _, err := NewEOPTable([]EOPEntry{{MJD: 2}, {MJD: 1}}); err == nil {
+		t.Error("unsorted table accepted")
+	}
+}